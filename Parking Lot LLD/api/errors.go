@@ -0,0 +1,80 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"parkinglot/entities"
+	"parkinglot/service"
+)
+
+// errBadRequest marks request bodies and parameters the API could not accept
+var errBadRequest = errors.New("bad request")
+
+// errorMapping ties a service error to the HTTP status and machine-readable code it is reported with
+type errorMapping struct {
+	err    error
+	status int
+	code   string
+}
+
+// errorMappings is checked in order with errors.Is, so wrapped errors map like their causes
+var errorMappings = []errorMapping{
+	{errBadRequest, http.StatusBadRequest, "bad_request"},
+	{service.ErrInvalidVehicle, http.StatusBadRequest, "invalid_vehicle"},
+	{entities.ErrInvalidVehicleType, http.StatusBadRequest, "invalid_vehicle_type"},
+	{service.ErrInvalidFloor, http.StatusBadRequest, "invalid_floor"},
+	{service.ErrNoPaymentProcessor, http.StatusBadRequest, "unsupported_payment_method"},
+	{service.ErrUnknownGate, http.StatusBadRequest, "unknown_gate"},
+	{service.ErrTicketNotFound, http.StatusNotFound, "ticket_not_found"},
+	{service.ErrVehicleNotParked, http.StatusNotFound, "vehicle_not_parked"},
+	{service.ErrParkingLotFull, http.StatusConflict, "parking_lot_full"},
+	{service.ErrVehicleAlreadyParked, http.StatusConflict, "vehicle_already_parked"},
+	{service.ErrTicketVoided, http.StatusConflict, "ticket_voided"},
+	{service.ErrNothingToPay, http.StatusConflict, "nothing_to_pay"},
+	{service.ErrPaymentRequired, http.StatusPaymentRequired, "payment_required"},
+	{service.ErrPaymentFailed, http.StatusPaymentRequired, "payment_failed"},
+}
+
+// errorResponse is the body of every error reply
+type errorResponse struct {
+	Error   errorBody        `json:"error"`
+	Payment *PaymentResponse `json:"payment,omitempty"` // The attempt made, when a payment got as far as the processor
+}
+
+type errorBody struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// writeError replies with the status and code mapped from err
+// Errors without a mapping are internal; their details are logged rather than sent to the client
+func (s *Server) writeError(w http.ResponseWriter, r *http.Request, err error) {
+	s.writeErrorResponse(w, r, err, errorResponse{})
+}
+
+// writePaymentError is writeError for a payment, adding the attempt when one was made so the
+// client gets its reference or failure reason along with the status
+func (s *Server) writePaymentError(w http.ResponseWriter, r *http.Request, err error, payment *entities.Payment) {
+	var resp errorResponse
+	if payment != nil {
+		attempt := paymentResponse(payment)
+		resp.Payment = &attempt
+	}
+	s.writeErrorResponse(w, r, err, resp)
+}
+
+// writeErrorResponse fills in the error of resp from err and sends it with the mapped status
+func (s *Server) writeErrorResponse(w http.ResponseWriter, r *http.Request, err error, resp errorResponse) {
+	for _, mapping := range errorMappings {
+		if errors.Is(err, mapping.err) {
+			resp.Error = errorBody{Code: mapping.code, Message: err.Error()}
+			writeJSON(w, mapping.status, resp)
+			return
+		}
+	}
+
+	s.logger.Printf("%s %s: %v", r.Method, r.URL.Path, err)
+	resp.Error = errorBody{Code: "internal", Message: "internal server error"}
+	writeJSON(w, http.StatusInternalServerError, resp)
+}
@@ -0,0 +1,274 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"parkinglot/entities"
+	"parkinglot/service"
+)
+
+// ParkRequest is the body of POST /v1/park
+type ParkRequest struct {
+	NumberPlate  string   `json:"number_plate"`
+	VehicleType  string   `json:"vehicle_type"`
+	Spots        int      `json:"spots,omitempty"` // Adjacent spots a truck needs; defaults to 1
+	Require      []string `json:"require,omitempty"`
+	Prefer       []string `json:"prefer,omitempty"`
+	MinChargerKW float64  `json:"min_charger_kw,omitempty"`
+	EntryGateID  string   `json:"entry_gate_id,omitempty"`
+}
+
+// UnparkRequest is the body of POST /v1/unpark
+type UnparkRequest struct {
+	TicketID   string `json:"ticket_id"`
+	ExitGateID string `json:"exit_gate_id,omitempty"`
+}
+
+// PayRequest is the body of POST /v1/tickets/{id}/payments
+type PayRequest struct {
+	Method string `json:"method"`
+	Amount int    `json:"amount,omitempty"` // Cents; 0 pays the full balance
+}
+
+// TicketResponse describes a ticket
+type TicketResponse struct {
+	ID           string     `json:"id"`
+	Status       string     `json:"status"` // "active", "exited" or "voided"
+	NumberPlate  string     `json:"number_plate"`
+	VehicleType  string     `json:"vehicle_type"`
+	FloorID      int        `json:"floor_id"`
+	SpotType     string     `json:"spot_type"`
+	SpotIDs      []int      `json:"spot_ids"`
+	EntryTime    time.Time  `json:"entry_time"`
+	ExitTime     *time.Time `json:"exit_time,omitempty"`
+	PricePerHour int        `json:"price_per_hour"`
+	PaidAmount   int        `json:"paid_amount"`
+	AmountDue    int        `json:"amount_due"`
+	Fee          int        `json:"fee,omitempty"`
+	ReplacedByID string     `json:"replaced_by_id,omitempty"`
+	EntryGateID  string     `json:"entry_gate_id,omitempty"`
+	ExitGateID   string     `json:"exit_gate_id,omitempty"`
+}
+
+// PaymentResponse describes a payment attempt
+type PaymentResponse struct {
+	ID            string    `json:"id"`
+	TicketID      string    `json:"ticket_id"`
+	Amount        int       `json:"amount"`
+	Method        string    `json:"method"`
+	Status        string    `json:"status"`
+	Reference     string    `json:"reference,omitempty"`
+	FailureReason string    `json:"failure_reason,omitempty"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// StatusResponse describes the occupancy of the whole lot
+type StatusResponse struct {
+	Floors             []FloorResponse `json:"floors"`
+	TotalActiveTickets int             `json:"total_active_tickets"`
+}
+
+// FloorResponse describes the occupancy of one floor by spot kind
+type FloorResponse struct {
+	FloorID int                     `json:"floor_id"`
+	Name    string                  `json:"name,omitempty"`
+	Closed  bool                    `json:"closed"`
+	Spots   map[string]SpotsSummary `json:"spots"`
+}
+
+// SpotsSummary counts the spots of one kind by state
+type SpotsSummary struct {
+	Total        int `json:"total"`
+	Occupied     int `json:"occupied"`
+	Vacant       int `json:"vacant"`
+	Reserved     int `json:"reserved"`
+	OutOfService int `json:"out_of_service"`
+	Blocked      int `json:"blocked"`
+}
+
+func (s *Server) handlePark(w http.ResponseWriter, r *http.Request) {
+	var req ParkRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		s.writeError(w, r, err)
+		return
+	}
+
+	vehicle, opts, err := req.vehicle()
+	if err != nil {
+		s.writeError(w, r, err)
+		return
+	}
+	ticket, err := s.lot.ParkVehicleWithOptions(vehicle, opts)
+	if err != nil {
+		s.writeError(w, r, err)
+		return
+	}
+	w.Header().Set("Location", "/v1/tickets/"+ticket.ID)
+	writeJSON(w, http.StatusCreated, s.ticketResponse(ticket))
+}
+
+// vehicle builds the vehicle and park options a request describes
+func (req ParkRequest) vehicle() (entities.Vehicle, service.ParkOptions, error) {
+	plate := strings.TrimSpace(req.NumberPlate)
+	if plate == "" {
+		return nil, service.ParkOptions{}, fmt.Errorf("%w: number_plate is required", service.ErrInvalidVehicle)
+	}
+	vehicleType, ok := entities.VehicleTypeByName(req.VehicleType)
+	if !ok {
+		return nil, service.ParkOptions{}, fmt.Errorf("%w: %q", entities.ErrInvalidVehicleType, req.VehicleType)
+	}
+	spots := req.Spots
+	if spots == 0 {
+		spots = 1
+	}
+	if spots < 0 {
+		return nil, service.ParkOptions{}, fmt.Errorf("%w: spots must be positive", errBadRequest)
+	}
+	vehicle, err := entities.NewVehicleOfType(vehicleType, plate, spots)
+	if err != nil {
+		return nil, service.ParkOptions{}, err
+	}
+
+	opts := service.ParkOptions{EntryGateID: req.EntryGateID}
+	if opts.Requirements.Require, err = entities.ParseSpotAttributes(req.Require...); err != nil {
+		return nil, service.ParkOptions{}, fmt.Errorf("%w: %v", errBadRequest, err)
+	}
+	if opts.Requirements.Prefer, err = entities.ParseSpotAttributes(req.Prefer...); err != nil {
+		return nil, service.ParkOptions{}, fmt.Errorf("%w: %v", errBadRequest, err)
+	}
+	opts.Requirements.MinChargerKW = req.MinChargerKW
+	return vehicle, opts, nil
+}
+
+func (s *Server) handleUnpark(w http.ResponseWriter, r *http.Request) {
+	var req UnparkRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		s.writeError(w, r, err)
+		return
+	}
+	if req.TicketID == "" {
+		s.writeError(w, r, fmt.Errorf("%w: ticket_id is required", errBadRequest))
+		return
+	}
+
+	ticket, _, err := s.lot.UnparkVehicleWithOptions(req.TicketID, service.UnparkOptions{ExitGateID: req.ExitGateID})
+	if err != nil {
+		s.writeError(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, s.ticketResponse(ticket))
+}
+
+func (s *Server) handlePay(w http.ResponseWriter, r *http.Request) {
+	var req PayRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		s.writeError(w, r, err)
+		return
+	}
+	if req.Amount < 0 {
+		s.writeError(w, r, fmt.Errorf("%w: amount must not be negative", errBadRequest))
+		return
+	}
+
+	method := entities.PaymentMethod(strings.ToLower(strings.TrimSpace(req.Method)))
+	payment, err := s.lot.PayTicket(r.PathValue("id"), method, req.Amount)
+	if err != nil {
+		s.writePaymentError(w, r, err, payment)
+		return
+	}
+	writeJSON(w, http.StatusCreated, paymentResponse(payment))
+}
+
+func (s *Server) handleGetTicket(w http.ResponseWriter, r *http.Request) {
+	ticket, err := s.lot.GetTicket(r.PathValue("id"))
+	if err != nil {
+		s.writeError(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, s.ticketResponse(ticket))
+}
+
+func (s *Server) handleGetActiveTicket(w http.ResponseWriter, r *http.Request) {
+	ticket, err := s.lot.GetActiveTicketByVehicle(r.PathValue("plate"))
+	if err != nil {
+		s.writeError(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, s.ticketResponse(ticket))
+}
+
+func (s *Server) handleStatus(w http.ResponseWriter, r *http.Request) {
+	status := s.lot.GetParkingLotStatus()
+	resp := StatusResponse{
+		Floors:             make([]FloorResponse, len(status.Floors)),
+		TotalActiveTickets: status.TotalActiveTickets,
+	}
+	for i, floor := range status.Floors {
+		floorResp := FloorResponse{FloorID: floor.FloorID, Name: floor.Name, Closed: floor.Closed, Spots: make(map[string]SpotsSummary, len(floor.Spots))}
+		for spotType, spots := range floor.Spots {
+			floorResp.Spots[spotType.String()] = SpotsSummary{
+				Total:        spots.Total,
+				Occupied:     spots.Occupied,
+				Vacant:       spots.Vacant,
+				Reserved:     spots.Reserved,
+				OutOfService: spots.OutOfService,
+				Blocked:      spots.Blocked,
+			}
+		}
+		resp.Floors[i] = floorResp
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+// ticketResponse describes a ticket, including what is due if the vehicle left now
+func (s *Server) ticketResponse(ticket *entities.Ticket) TicketResponse {
+	resp := TicketResponse{
+		ID:           ticket.ID,
+		Status:       "active",
+		NumberPlate:  ticket.Vehicle.GetNumberPlate(),
+		VehicleType:  ticket.VehicleType.String(),
+		FloorID:      ticket.FloorID,
+		SpotType:     ticket.SpotType.String(),
+		SpotIDs:      ticket.SpotIDs,
+		EntryTime:    ticket.EntryTime,
+		PricePerHour: ticket.PricePerHour,
+		PaidAmount:   ticket.PaidAmount,
+		Fee:          ticket.Fee,
+		ReplacedByID: ticket.ReplacedByID,
+		EntryGateID:  ticket.EntryGateID,
+		ExitGateID:   ticket.ExitGateID,
+	}
+	switch {
+	case ticket.IsVoided():
+		resp.Status = "voided"
+	case !ticket.IsActive():
+		resp.Status = "exited"
+		exitTime := ticket.ExitTime
+		resp.ExitTime = &exitTime
+	default:
+		if due, err := s.lot.AmountDue(ticket.ID); err == nil {
+			resp.AmountDue = due
+		}
+	}
+	return resp
+}
+
+func paymentResponse(payment *entities.Payment) PaymentResponse {
+	return PaymentResponse{
+		ID:            payment.ID,
+		TicketID:      payment.TicketID,
+		Amount:        payment.Amount,
+		Method:        string(payment.Method),
+		Status:        string(payment.Status),
+		Reference:     payment.Reference,
+		FailureReason: payment.FailureReason,
+		CreatedAt:     payment.CreatedAt,
+	}
+}
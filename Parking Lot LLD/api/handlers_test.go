@@ -0,0 +1,147 @@
+package api
+
+import (
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"parkinglot/entities"
+	"parkinglot/service"
+)
+
+// newTestServer creates a server for a one-floor lot with two car spots on a manual clock
+func newTestServer(t *testing.T, opts ...service.Option) (*Server, *entities.ManualClock) {
+	t.Helper()
+	clock := entities.NewManualClock(time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC))
+	lot := service.NewParkingLotService([][3]int{{2, 0, 0}}, nil, append([]service.Option{service.WithClock(clock)}, opts...)...)
+	return NewServer(lot, log.New(io.Discard, "", 0)), clock
+}
+
+// do sends a request with a JSON body, or none when body is empty, and decodes the reply into out
+func do(t *testing.T, s *Server, method, path, body string, out any) int {
+	t.Helper()
+	var reader io.Reader
+	if body != "" {
+		reader = strings.NewReader(body)
+	}
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, httptest.NewRequest(method, path, reader))
+	if out != nil {
+		if err := json.Unmarshal(rec.Body.Bytes(), out); err != nil {
+			t.Fatalf("%s %s: decoding %q: %v", method, path, rec.Body.String(), err)
+		}
+	}
+	return rec.Code
+}
+
+// park parks a car through the API and returns its ticket
+func park(t *testing.T, s *Server, plate string) TicketResponse {
+	t.Helper()
+	var ticket TicketResponse
+	if status := do(t, s, http.MethodPost, "/v1/park", `{"number_plate": "`+plate+`", "vehicle_type": "car"}`, &ticket); status != http.StatusCreated {
+		t.Fatalf("parking %s: status %d", plate, status)
+	}
+	return ticket
+}
+
+func TestFailedPaymentReturnsAttempt(t *testing.T) {
+	s, clock := newTestServer(t, service.WithPaymentProcessor(&service.CardProcessor{FailNext: 1}))
+	ticket := park(t, s, "API-1")
+	clock.Advance(time.Hour)
+
+	var failed errorResponse
+	status := do(t, s, http.MethodPost, "/v1/tickets/"+ticket.ID+"/payments", `{"method": "card"}`, &failed)
+	if status != http.StatusPaymentRequired || failed.Error.Code != "payment_failed" {
+		t.Fatalf("declined card: status %d, code %q; want 402 payment_failed", status, failed.Error.Code)
+	}
+	if failed.Payment == nil || failed.Payment.Status != string(entities.PaymentFailed) || failed.Payment.FailureReason == "" {
+		t.Fatalf("declined card reply has payment %+v, want the failed attempt with its reason", failed.Payment)
+	}
+
+	var retry PaymentResponse
+	if status := do(t, s, http.MethodPost, "/v1/tickets/"+ticket.ID+"/payments", `{"method": "card"}`, &retry); status != http.StatusCreated {
+		t.Fatalf("retry: status %d", status)
+	}
+	if retry.ID == failed.Payment.ID || retry.Status != string(entities.PaymentCompleted) {
+		t.Errorf("retry is %+v, want a new completed payment", retry)
+	}
+}
+
+func TestParkPayUnpark(t *testing.T) {
+	s, clock := newTestServer(t)
+	ticket := park(t, s, "API-1")
+	if ticket.Status != "active" || ticket.NumberPlate != "API-1" || len(ticket.SpotIDs) != 1 {
+		t.Fatalf("parked ticket %+v, want an active ticket for API-1 in one spot", ticket)
+	}
+	clock.Advance(90 * time.Minute)
+
+	var fetched TicketResponse
+	if status := do(t, s, http.MethodGet, "/v1/tickets/"+ticket.ID, "", &fetched); status != http.StatusOK || fetched.AmountDue != 40 {
+		t.Fatalf("ticket after 90 minutes: status %d, %d due; want 200 and 40", status, fetched.AmountDue)
+	}
+	var payment PaymentResponse
+	if status := do(t, s, http.MethodPost, "/v1/tickets/"+ticket.ID+"/payments", `{"method": "cash"}`, &payment); status != http.StatusCreated {
+		t.Fatalf("paying: status %d", status)
+	}
+	if payment.Amount != 40 || payment.Status != string(entities.PaymentCompleted) {
+		t.Errorf("payment %+v, want 40 completed", payment)
+	}
+
+	var exited TicketResponse
+	if status := do(t, s, http.MethodPost, "/v1/unpark", `{"ticket_id": "`+ticket.ID+`"}`, &exited); status != http.StatusOK {
+		t.Fatalf("unparking: status %d", status)
+	}
+	if exited.Status != "exited" || exited.ExitTime == nil || exited.PaidAmount != 40 || exited.AmountDue != 0 {
+		t.Errorf("unparked ticket %+v, want exited with 40 paid and nothing due", exited)
+	}
+	var status StatusResponse
+	do(t, s, http.MethodGet, "/v1/status", "", &status)
+	if status.TotalActiveTickets != 0 {
+		t.Errorf("%d active tickets after the exit, want 0", status.TotalActiveTickets)
+	}
+}
+
+func TestErrorStatuses(t *testing.T) {
+	s, _ := newTestServer(t)
+	ticket := park(t, s, "API-1")
+	settled := park(t, s, "API-2")
+	if status := do(t, s, http.MethodPost, "/v1/tickets/"+settled.ID+"/payments", `{"method": "cash"}`, nil); status != http.StatusCreated {
+		t.Fatalf("paying %s: status %d", settled.ID, status)
+	}
+
+	tests := []struct {
+		name         string
+		method, path string
+		body         string
+		wantStatus   int
+		wantCode     string
+	}{
+		{"unknown ticket", http.MethodGet, "/v1/tickets/missing", "", http.StatusNotFound, "ticket_not_found"},
+		{"vehicle not parked", http.MethodGet, "/v1/vehicles/NOBODY/ticket", "", http.StatusNotFound, "vehicle_not_parked"},
+		{"already parked", http.MethodPost, "/v1/park", `{"number_plate": "API-1", "vehicle_type": "car"}`, http.StatusConflict, "vehicle_already_parked"},
+		{"nothing to pay", http.MethodPost, "/v1/tickets/" + settled.ID + "/payments", `{"method": "cash"}`, http.StatusConflict, "nothing_to_pay"},
+		{"unpaid exit", http.MethodPost, "/v1/unpark", `{"ticket_id": "` + ticket.ID + `"}`, http.StatusPaymentRequired, "payment_required"},
+		{"malformed body", http.MethodPost, "/v1/park", `{"number_plate": "API-3",`, http.StatusBadRequest, "bad_request"},
+		{"unknown field", http.MethodPost, "/v1/park", `{"number_plate": "API-3", "vehicle_type": "car", "colour": "red"}`, http.StatusBadRequest, "bad_request"},
+		{"negative amount", http.MethodPost, "/v1/tickets/" + ticket.ID + "/payments", `{"method": "cash", "amount": -5}`, http.StatusBadRequest, "bad_request"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var reply errorResponse
+			if status := do(t, s, tt.method, tt.path, tt.body, &reply); status != tt.wantStatus || reply.Error.Code != tt.wantCode {
+				t.Errorf("status %d, code %q; want %d %s", status, reply.Error.Code, tt.wantStatus, tt.wantCode)
+			}
+		})
+	}
+
+	// None of the rejected requests changed anything
+	var still TicketResponse
+	if do(t, s, http.MethodGet, "/v1/tickets/"+ticket.ID, "", &still); still.Status != "active" || still.PaidAmount != 0 {
+		t.Errorf("ticket after the rejected requests %+v, want active with nothing paid", still)
+	}
+}
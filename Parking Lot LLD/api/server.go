@@ -0,0 +1,72 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+
+	"parkinglot/service"
+)
+
+// maxBodyBytes bounds request bodies; every request this API takes is a small JSON object
+const maxBodyBytes = 1 << 20
+
+// Server exposes a parking lot over an HTTP JSON API
+//
+//	POST /v1/park                    park a vehicle, returning its ticket
+//	POST /v1/unpark                  release a paid ticket's vehicle
+//	POST /v1/tickets/{id}/payments   pay toward a ticket
+//	GET  /v1/tickets/{id}            look up a ticket
+//	GET  /v1/vehicles/{plate}/ticket look up a parked vehicle's active ticket
+//	GET  /v1/status                  occupancy of every floor
+//	GET  /healthz                    liveness check
+type Server struct {
+	lot    *service.ParkingLotService
+	mux    *http.ServeMux
+	logger *log.Logger
+}
+
+// NewServer creates an API server for a lot
+// Unexpected errors are logged to logger, or to the standard logger when it is nil
+func NewServer(lot *service.ParkingLotService, logger *log.Logger) *Server {
+	if logger == nil {
+		logger = log.Default()
+	}
+
+	s := &Server{lot: lot, mux: http.NewServeMux(), logger: logger}
+	s.mux.HandleFunc("POST /v1/park", s.handlePark)
+	s.mux.HandleFunc("POST /v1/unpark", s.handleUnpark)
+	s.mux.HandleFunc("POST /v1/tickets/{id}/payments", s.handlePay)
+	s.mux.HandleFunc("GET /v1/tickets/{id}", s.handleGetTicket)
+	s.mux.HandleFunc("GET /v1/vehicles/{plate}/ticket", s.handleGetActiveTicket)
+	s.mux.HandleFunc("GET /v1/status", s.handleStatus)
+	s.mux.HandleFunc("GET /healthz", s.handleHealth)
+	return s
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+// decodeJSON reads a request body into v, rejecting unknown fields and trailing data
+func decodeJSON(w http.ResponseWriter, r *http.Request, v any) error {
+	decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBodyBytes))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(v); err != nil {
+		return fmt.Errorf("%w: invalid JSON body: %v", errBadRequest, err)
+	}
+	if _, err := decoder.Token(); !errors.Is(err, io.EOF) {
+		return fmt.Errorf("%w: body must hold a single JSON object", errBadRequest)
+	}
+	return nil
+}
+
+// writeJSON replies with v encoded as JSON
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
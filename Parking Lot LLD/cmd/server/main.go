@@ -0,0 +1,152 @@
+// Command server runs the parking lot HTTP API, and the gRPC API when -grpc-addr is set
+//
+//	server -addr :8080 -grpc-addr :9090 -floors 10/20/5,15/25/3
+//	server -addr :8080 -config lot.yaml
+//
+// Each floor in -floors is "cars/motorcycles/trucks"; -config describes the whole lot instead, see
+// service.LotConfig. Both APIs share one lot. On SIGINT or
+// SIGTERM the servers stop accepting connections and wait up to -shutdown-timeout for requests in
+// flight to finish
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"google.golang.org/grpc"
+
+	"parkinglot/api"
+	"parkinglot/grpcserver"
+	"parkinglot/lotconfig"
+	"parkinglot/service"
+)
+
+func main() {
+	addr := flag.String("addr", ":8080", "address to listen on")
+	grpcAddr := flag.String("grpc-addr", "", "address to serve gRPC on; empty disables it")
+	floors := flag.String("floors", "10/20/5,15/25/3,20/30/2", "comma-separated floors, each cars/motorcycles/trucks")
+	configPath := flag.String("config", "", "YAML or JSON lot configuration; overrides -floors")
+	shutdownTimeout := flag.Duration("shutdown-timeout", 10*time.Second, "how long to wait for requests in flight on shutdown")
+	flag.Parse()
+
+	logger := log.New(os.Stderr, "parking-lot ", log.LstdFlags)
+
+	lot, err := newLot(*configPath, *floors)
+	if err != nil {
+		logger.Fatal(err)
+	}
+
+	server := &http.Server{
+		Addr:              *addr,
+		Handler:           api.NewServer(lot, logger),
+		ReadHeaderTimeout: 5 * time.Second,
+		ErrorLog:          logger,
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	serveErr := make(chan error, 2)
+	go func() {
+		logger.Printf("listening on %s with %d floors", *addr, len(lot.GetParkingLotStatus().Floors))
+		serveErr <- server.ListenAndServe()
+	}()
+
+	var grpcServer *grpc.Server
+	var parkingService *grpcserver.Server
+	if *grpcAddr != "" {
+		listener, err := net.Listen("tcp", *grpcAddr)
+		if err != nil {
+			logger.Fatalf("gRPC listen failed: %v", err)
+		}
+		grpcServer = grpc.NewServer()
+		parkingService = grpcserver.NewServer(lot, logger)
+		parkingService.Register(grpcServer)
+		go func() {
+			logger.Printf("serving gRPC on %s", *grpcAddr)
+			serveErr <- grpcServer.Serve(listener)
+		}()
+	}
+
+	select {
+	case err := <-serveErr:
+		if !errors.Is(err, http.ErrServerClosed) {
+			logger.Fatalf("server failed: %v", err)
+		}
+	case <-ctx.Done():
+		logger.Printf("shutting down")
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), *shutdownTimeout)
+		defer cancel()
+		if err := server.Shutdown(shutdownCtx); err != nil {
+			logger.Printf("shutdown incomplete: %v", err)
+		}
+		if grpcServer != nil {
+			stopGRPC(shutdownCtx, grpcServer, parkingService)
+		}
+	}
+}
+
+// stopGRPC stops a gRPC server gracefully, cutting off calls still running when ctx ends
+// Availability streams never finish on their own, so they are ended first and only unary calls are waited for
+func stopGRPC(ctx context.Context, server *grpc.Server, parkingService *grpcserver.Server) {
+	parkingService.Close()
+	stopped := make(chan struct{})
+	go func() {
+		server.GracefulStop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-ctx.Done():
+		server.Stop()
+	}
+}
+
+// newLot builds the lot from a configuration file, or from a floors spec when there is none
+func newLot(configPath, floors string) (*service.ParkingLotService, error) {
+	if configPath != "" {
+		config, err := lotconfig.Load(configPath)
+		if err != nil {
+			return nil, err
+		}
+		return service.NewParkingLotServiceFromConfig(config)
+	}
+
+	floorsConfig, err := parseFloors(floors)
+	if err != nil {
+		return nil, fmt.Errorf("invalid -floors: %w", err)
+	}
+	return service.NewParkingLotService(floorsConfig, nil), nil
+}
+
+// parseFloors parses "10/20/5,15/25/3" into per-floor [cars, motorcycles, trucks] capacities
+func parseFloors(spec string) ([][3]int, error) {
+	var floors [][3]int
+	for i, floor := range strings.Split(spec, ",") {
+		parts := strings.Split(strings.TrimSpace(floor), "/")
+		if len(parts) != 3 {
+			return nil, fmt.Errorf("floor %d: want cars/motorcycles/trucks, got %q", i+1, floor)
+		}
+		var capacities [3]int
+		for j, part := range parts {
+			n, err := strconv.Atoi(strings.TrimSpace(part))
+			if err != nil || n < 0 {
+				return nil, fmt.Errorf("floor %d: invalid capacity %q", i+1, part)
+			}
+			capacities[j] = n
+		}
+		floors = append(floors, capacities)
+	}
+	return floors, nil
+}
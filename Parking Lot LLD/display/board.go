@@ -0,0 +1,158 @@
+package display
+
+import (
+	"sort"
+	"sync"
+
+	"parkinglot/entities"
+	"parkinglot/service"
+)
+
+// SpotCount is the vacancy of one spot kind on a floor
+type SpotCount struct {
+	SpotType entities.VehicleType
+	Vacant   int
+	Total    int
+}
+
+// FloorRow is one floor's line on a board
+type FloorRow struct {
+	FloorID int
+	Spots   []SpotCount // In registry order
+}
+
+// BoardView is what a board shows at a moment, ready to be rendered
+type BoardView struct {
+	Title string
+	Rows  []FloorRow
+}
+
+// Board shows live vacancy per vehicle type for one or more floors
+// It reads the lot's status once when created and then follows AvailabilityChanged events,
+// so each park or exit updates a single count instead of the board polling the lot
+// Events can arrive out of order, so a count is only replaced by one from a later event
+type Board struct {
+	title   string
+	floors  map[int]bool // floors shown; nil shows every floor
+	vacancy map[int]map[entities.VehicleType]SpotCount
+	applied map[countKey]uint64 // sequence number of the event or status each count came from
+	lot     *service.ParkingLotService
+	sub     *service.Subscription
+	mu      sync.RWMutex
+}
+
+// countKey identifies one count on a board
+type countKey struct {
+	floorID  int
+	spotType entities.VehicleType
+}
+
+// NewFloorBoard creates the sign at the entry of a floor, showing that floor only
+func NewFloorBoard(lot *service.ParkingLotService, floorID int, title string) *Board {
+	return newBoard(lot, title, []int{floorID})
+}
+
+// NewEntranceBoard creates the sign at a lot entrance, showing the given floors or every floor if none are given
+func NewEntranceBoard(lot *service.ParkingLotService, title string, floorIDs ...int) *Board {
+	return newBoard(lot, title, floorIDs)
+}
+
+func newBoard(lot *service.ParkingLotService, title string, floorIDs []int) *Board {
+	b := &Board{
+		title:   title,
+		vacancy: make(map[int]map[entities.VehicleType]SpotCount),
+		applied: make(map[countKey]uint64),
+		lot:     lot,
+	}
+	if len(floorIDs) > 0 {
+		b.floors = make(map[int]bool, len(floorIDs))
+		for _, floorID := range floorIDs {
+			b.floors[floorID] = true
+		}
+	}
+
+	// Subscribe before reading the status so no change falls in between; whichever of an event
+	// and the status is newer wins, since both carry absolute counts
+	b.sub = lot.Subscribe(b.handle, service.DeliverSync, service.EventAvailabilityChanged)
+
+	status := lot.GetParkingLotStatus()
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for _, floor := range status.Floors {
+		if !b.shows(floor.FloorID) {
+			continue
+		}
+		for spotType, spots := range floor.Spots {
+			b.apply(floor.FloorID, SpotCount{SpotType: spotType, Vacant: spots.Vacant, Total: spots.Total}, status.EventSeq)
+		}
+	}
+	return b
+}
+
+// handle applies an availability change to the board
+func (b *Board) handle(event service.Event) {
+	changed, ok := event.(service.AvailabilityChanged)
+	if !ok || !b.shows(changed.FloorID) {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.apply(changed.FloorID, SpotCount{SpotType: changed.SpotType, Vacant: changed.Vacant, Total: changed.Total}, changed.Seq)
+}
+
+// apply sets a count unless the board already shows one from a later event
+// A count from the status read at creation has the sequence number of the last event before it
+// Must be called with the board's lock held
+func (b *Board) apply(floorID int, count SpotCount, seq uint64) {
+	key := countKey{floorID, count.SpotType}
+	if last, ok := b.applied[key]; ok && seq <= last {
+		return
+	}
+	b.applied[key] = seq
+
+	counts, ok := b.vacancy[floorID]
+	if !ok {
+		counts = make(map[entities.VehicleType]SpotCount)
+		b.vacancy[floorID] = counts
+	}
+	counts[count.SpotType] = count
+}
+
+// shows reports whether the board displays a floor
+func (b *Board) shows(floorID int) bool {
+	return b.floors == nil || b.floors[floorID]
+}
+
+// Vacant returns the vacancy shown for a spot kind on a floor
+func (b *Board) Vacant(floorID int, spotType entities.VehicleType) int {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return b.vacancy[floorID][spotType].Vacant
+}
+
+// View returns what the board currently shows, floors in ascending order
+func (b *Board) View() BoardView {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	view := BoardView{Title: b.title, Rows: make([]FloorRow, 0, len(b.vacancy))}
+	for floorID, counts := range b.vacancy {
+		row := FloorRow{FloorID: floorID}
+		for _, info := range entities.VehicleTypes() {
+			if count, ok := counts[info.Type]; ok {
+				row.Spots = append(row.Spots, count)
+			}
+		}
+		view.Rows = append(view.Rows, row)
+	}
+	sort.Slice(view.Rows, func(i, j int) bool {
+		return view.Rows[i].FloorID < view.Rows[j].FloorID
+	})
+	return view
+}
+
+// Close stops the board from following the lot
+func (b *Board) Close() {
+	b.lot.Unsubscribe(b.sub)
+}
@@ -0,0 +1,76 @@
+package display
+
+import (
+	"testing"
+
+	"parkinglot/entities"
+	"parkinglot/service"
+)
+
+func TestBoardFollowsLot(t *testing.T) {
+	lot := service.NewParkingLotService([][3]int{{2, 1, 0}, {1, 0, 0}}, nil)
+	board := NewFloorBoard(lot, 1, "Floor 1")
+	defer board.Close()
+
+	ticket, err := lot.ParkVehicle(entities.NewCar("BOARD-1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if vacant := board.Vacant(1, entities.CAR); vacant != 1 {
+		t.Errorf("board shows %d car spots after a park, want 1", vacant)
+	}
+	if _, err := lot.ParkVehicle(entities.NewMotorCycle("BOARD-2")); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := board.String(), "Floor 1\n  Floor 1   Motorcycle FULL Car 1 Truck FULL\n"; got != want {
+		t.Errorf("board reads %q, want %q", got, want)
+	}
+
+	if _, err := lot.PayTicket(ticket.ID, entities.PaymentCash, 0); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := lot.UnparkVehicle(ticket.ID); err != nil {
+		t.Fatal(err)
+	}
+	if vacant := board.Vacant(1, entities.CAR); vacant != 2 {
+		t.Errorf("board shows %d car spots after an exit, want 2", vacant)
+	}
+}
+
+func TestEntranceBoardShowsChosenFloors(t *testing.T) {
+	lot := service.NewParkingLotService([][3]int{{1, 0, 0}, {2, 0, 0}, {3, 0, 0}}, nil)
+	board := NewEntranceBoard(lot, "North", 3, 1)
+	defer board.Close()
+
+	view := board.View()
+	if len(view.Rows) != 2 || view.Rows[0].FloorID != 1 || view.Rows[1].FloorID != 3 {
+		t.Fatalf("board rows %+v, want floors 1 and 3 in order", view.Rows)
+	}
+	// Every kind is listed in registry order, including ones the floor has no spots for
+	if spots := view.Rows[1].Spots; len(spots) != 3 || spots[1].SpotType != entities.CAR || spots[1].Vacant != 3 || spots[1].Total != 3 {
+		t.Errorf("floor 3 shows %+v, want 3 of 3 car spots", spots)
+	}
+}
+
+func TestBoardIgnoresOlderEvents(t *testing.T) {
+	lot := service.NewParkingLotService([][3]int{{3, 0, 0}}, nil)
+	if _, err := lot.ParkVehicle(entities.NewCar("BOARD-1")); err != nil {
+		t.Fatal(err)
+	}
+	board := NewFloorBoard(lot, 1, "")
+	defer board.Close()
+	seq := lot.GetParkingLotStatus().EventSeq
+
+	// An event raised before the status the board started from is already reflected in it
+	board.handle(service.AvailabilityChanged{Seq: seq, FloorID: 1, SpotType: entities.CAR, Vacant: 3, Total: 3})
+	if vacant := board.Vacant(1, entities.CAR); vacant != 2 {
+		t.Errorf("board shows %d after an event older than its status, want 2", vacant)
+	}
+
+	// Events from concurrent operations delivered in reverse keep the later count
+	board.handle(service.AvailabilityChanged{Seq: seq + 5, FloorID: 1, SpotType: entities.CAR, Vacant: 0, Total: 3})
+	board.handle(service.AvailabilityChanged{Seq: seq + 3, FloorID: 1, SpotType: entities.CAR, Vacant: 1, Total: 3})
+	if vacant := board.Vacant(1, entities.CAR); vacant != 0 {
+		t.Errorf("board shows %d after a late older event, want 0 from the newest", vacant)
+	}
+}
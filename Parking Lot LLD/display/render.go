@@ -0,0 +1,60 @@
+package display
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Renderer draws a board view on some output
+type Renderer interface {
+	Render(w io.Writer, view BoardView) error
+}
+
+// TextRenderer draws boards as plain text, one line per floor, e.g.
+//
+//	North entrance
+//	  Floor 1   Motorcycle 20 Car 10 Truck FULL
+type TextRenderer struct {
+	ShowTotals bool // Show "vacant/total" instead of just the vacant count
+}
+
+// NewTextRenderer creates a TextRenderer that shows vacant counts only
+func NewTextRenderer() *TextRenderer {
+	return &TextRenderer{}
+}
+
+func (r *TextRenderer) Render(w io.Writer, view BoardView) error {
+	var sb strings.Builder
+	if view.Title != "" {
+		sb.WriteString(view.Title)
+		sb.WriteString("\n")
+	}
+	for _, row := range view.Rows {
+		fmt.Fprintf(&sb, "  Floor %-3d", row.FloorID)
+		for _, spots := range row.Spots {
+			fmt.Fprintf(&sb, " %s %s", spots.SpotType, r.count(spots))
+		}
+		sb.WriteString("\n")
+	}
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// count formats one spot kind's vacancy, showing FULL when nothing is vacant
+func (r *TextRenderer) count(spots SpotCount) string {
+	if spots.Vacant == 0 {
+		return "FULL"
+	}
+	if r.ShowTotals {
+		return fmt.Sprintf("%d/%d", spots.Vacant, spots.Total)
+	}
+	return fmt.Sprintf("%d", spots.Vacant)
+}
+
+// String renders the board as text
+func (b *Board) String() string {
+	var sb strings.Builder
+	NewTextRenderer().Render(&sb, b.View())
+	return sb.String()
+}
@@ -0,0 +1,56 @@
+package entities
+
+import (
+	"sync"
+	"time"
+)
+
+// Clock supplies the current time to every time-dependent part of the parking lot
+// Injecting it lets tests and simulations control time instead of sleeping
+type Clock interface {
+	Now() time.Time
+}
+
+// RealClock reads the system clock
+type RealClock struct{}
+
+// NewRealClock creates a new RealClock
+func NewRealClock() RealClock {
+	return RealClock{}
+}
+
+func (RealClock) Now() time.Time {
+	return time.Now()
+}
+
+// ManualClock only moves when told to, for tests and replayable simulations
+type ManualClock struct {
+	now time.Time
+	mu  sync.RWMutex
+}
+
+// NewManualClock creates a ManualClock starting at the given time
+func NewManualClock(start time.Time) *ManualClock {
+	return &ManualClock{now: start}
+}
+
+func (c *ManualClock) Now() time.Time {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.now
+}
+
+// Set moves the clock to the given time
+func (c *ManualClock) Set(t time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = t
+}
+
+// Advance moves the clock forward by d and returns the new time
+func (c *ManualClock) Advance(d time.Duration) time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+	return c.now
+}
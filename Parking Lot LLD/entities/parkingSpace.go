@@ -3,32 +3,39 @@ package entities
 // ParkingSpace represents a single floor in the parking lot
 // Each floor can have multiple spots of different vehicle types
 type ParkingSpace struct {
-	ID             int
-	CarSpots       *CarSpot
-	MotorBikeSpots *MotorCycleSpot
-	TruckSpots     *TruckSpot
+	ID     int
+	Name   string                          // Display name, e.g. "Level B1"; empty when the floor has none
+	Spots  map[VehicleType]*SpotCollection // spot collections keyed by the vehicle type they serve
+	Closed bool                            // Closed floors take no vehicles; their spots are out of service
 }
 
-// NewParkingSpace creates a new parking space (floor) with specified capacities
-func NewParkingSpace(floorID, carCapacity, motorcycleCapacity, truckCapacity int) *ParkingSpace {
+// NewParkingSpace creates a new parking space (floor) with specified capacities per vehicle type
+func NewParkingSpace(floorID int, capacities map[VehicleType]int) *ParkingSpace {
+	spots := make(map[VehicleType]*SpotCollection, len(capacities))
+	for vehicleType, capacity := range capacities {
+		spots[vehicleType] = NewSpotCollection(vehicleType, capacity)
+	}
 	return &ParkingSpace{
-		ID:             floorID,
-		CarSpots:       NewCarSpot(carCapacity),
-		MotorBikeSpots: NewMotorCycleSpot(motorcycleCapacity),
-		TruckSpots:     NewTruckSpot(truckCapacity),
+		ID:    floorID,
+		Spots: spots,
 	}
 }
 
 // GetSpotByVehicleType returns the appropriate spot collection based on vehicle type
+// Returns nil if the floor has no spots for that type
 func (ps *ParkingSpace) GetSpotByVehicleType(vehicleType VehicleType) ParkingSpot {
-	switch vehicleType {
-	case MOTORCYCLE:
-		return ps.MotorBikeSpots
-	case CAR:
-		return ps.CarSpots
-	case TRUCK:
-		return ps.TruckSpots
-	default:
+	spots, ok := ps.Spots[vehicleType]
+	if !ok {
 		return nil
 	}
+	return spots
+}
+
+// SetClosed closes or reopens the floor
+// Closing takes every vacant spot out of service, and spots freed while closed follow them
+func (ps *ParkingSpace) SetClosed(closed bool) {
+	ps.Closed = closed
+	for _, spots := range ps.Spots {
+		spots.SetClosed(closed)
+	}
 }
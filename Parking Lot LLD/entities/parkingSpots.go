@@ -2,6 +2,7 @@ package entities
 
 import (
 	"errors"
+	"fmt"
 	"sync"
 )
 
@@ -10,359 +11,541 @@ var (
 	ErrSpotNotFound        = errors.New("spot not found")
 	ErrSpotAlreadyOccupied = errors.New("spot is already occupied")
 	ErrSpotAlreadyVacant   = errors.New("spot is already vacant")
+	ErrSpotNotReserved     = errors.New("spot is not reserved")
 )
 
 // ParkingSpot interface defines methods for managing parking spots
 type ParkingSpot interface {
+	Kind() VehicleType
 	FindVacantSpot() (int, error)
+	FindVacantRun(length int) (int, error)
+	FindMatchingRun(length int, req SpotRequirements) (int, error)
+	FindMatchingRunAfter(after, length int, req SpotRequirements) (int, error)
 	OccupySpot(spotID int, vehicle Vehicle) error
+	OccupySpots(spotIDs []int, vehicle Vehicle) error
 	ReleaseSpot(spotID int) error
+	ReleaseSpots(spotIDs []int) error
+	ReserveSpots(spotIDs []int) error
+	UnreserveSpots(spotIDs []int) error
+	SetSpotsState(spotIDs []int, state SpotState) error
+	SetClosed(closed bool)
+	IsClosed() bool
 	IsOccupied(spotID int) bool
+	IsReserved(spotID int) bool
+	State(spotID int) SpotState
 	GetVehicle(spotID int) (Vehicle, error)
 	GetTotalSpots() int
 	GetOccupiedCount() int
 	GetVacantCount() int
+	GetReservedCount() int
+	GetStateCount(state SpotState) int
+	AttributeCounts() map[SpotAttributes]AttributeCount
 }
 
 // Spot represents a single parking spot
 type Spot struct {
-	ID       int
-	Occupied bool
-	Vehicle  Vehicle
-}
-
-// MotorCycleSpot manages multiple motorcycle parking spots
-type MotorCycleSpot struct {
-	spots []*Spot
-	mu    sync.RWMutex
-}
-
-// NewMotorCycleSpot creates a new MotorCycleSpot with specified capacity
-func NewMotorCycleSpot(capacity int) *MotorCycleSpot {
+	ID         int
+	State      SpotState
+	Vehicle    Vehicle
+	Attributes SpotAttributes // Features the spot offers
+	ChargerKW  float64        // Charger power rating when the spot has EVCharger
+}
+
+// SpotCollection manages all parking spots of one kind on a floor
+// A single implementation backs every vehicle type; the kind decides which vehicles it serves
+// Available spots are tracked in a bitmap and every state in a counter, so allocation and
+// counts don't depend on how many spots the collection holds
+// Spots are laid out as a single row in ID order, so consecutive IDs are adjacent spots
+// A closed collection sends every spot that is freed to OutOfService instead of Available
+type SpotCollection struct {
+	kind       VehicleType
+	spots      []*Spot
+	vacant     *spotBitmap                    // bit (spotID-1) is set while the spot is Available
+	attributes map[SpotAttributes]*spotBitmap // single attribute -> spots that offer it
+	counts     [spotStateCount]int            // number of spots in each state
+	closed     bool
+	closedOut  *spotBitmap // spots taken out of service by closing the collection, restored on reopening
+	mu         sync.RWMutex
+}
+
+// NewSpotCollection creates a new SpotCollection of the given kind with specified capacity
+func NewSpotCollection(kind VehicleType, capacity int) *SpotCollection {
 	spots := make([]*Spot, capacity)
+	vacant := newSpotBitmap(capacity)
 	for i := 0; i < capacity; i++ {
-		spots[i] = &Spot{ID: i + 1, Occupied: false}
+		spots[i] = &Spot{ID: i + 1, State: SpotAvailable}
+		vacant.set(i)
 	}
-	return &MotorCycleSpot{spots: spots}
+	sc := &SpotCollection{
+		kind:       kind,
+		spots:      spots,
+		vacant:     vacant,
+		attributes: make(map[SpotAttributes]*spotBitmap),
+		closedOut:  newSpotBitmap(capacity),
+	}
+	sc.counts[SpotAvailable] = capacity
+	return sc
 }
 
-func (m *MotorCycleSpot) FindVacantSpot() (int, error) {
-	m.mu.Lock()
-	defer m.mu.Unlock()
-
-	for _, spot := range m.spots {
-		if !spot.Occupied {
-			return spot.ID, nil
-		}
-	}
-	return 0, ErrNoVacantSpot
+// Kind returns the vehicle type this collection is configured for
+func (sc *SpotCollection) Kind() VehicleType {
+	return sc.kind
 }
 
-func (m *MotorCycleSpot) OccupySpot(spotID int, vehicle Vehicle) error {
-	m.mu.Lock()
-	defer m.mu.Unlock()
+// FindVacantSpot returns the lowest numbered vacant spot
+func (sc *SpotCollection) FindVacantSpot() (int, error) {
+	sc.mu.RLock()
+	defer sc.mu.RUnlock()
 
-	if spotID < 1 || spotID > len(m.spots) {
-		return ErrSpotNotFound
+	index := sc.vacant.first()
+	if index < 0 {
+		return 0, ErrNoVacantSpot
 	}
+	return index + 1, nil
+}
 
-	spot := m.spots[spotID-1]
-	if spot.Occupied {
-		return ErrSpotAlreadyOccupied
+// FindVacantRun returns the first spot ID of the lowest run of length adjacent vacant spots
+func (sc *SpotCollection) FindVacantRun(length int) (int, error) {
+	if length < 1 {
+		length = 1
 	}
 
-	spot.Occupied = true
-	spot.Vehicle = vehicle
-	return nil
-}
-
-func (m *MotorCycleSpot) ReleaseSpot(spotID int) error {
-	m.mu.Lock()
-	defer m.mu.Unlock()
+	sc.mu.RLock()
+	defer sc.mu.RUnlock()
 
-	if spotID < 1 || spotID > len(m.spots) {
-		return ErrSpotNotFound
+	index := sc.vacant.firstRun(length)
+	if index < 0 || index+length > len(sc.spots) {
+		return 0, ErrNoVacantSpot
 	}
+	return index + 1, nil
+}
 
-	spot := m.spots[spotID-1]
-	if !spot.Occupied {
-		return ErrSpotAlreadyVacant
+// FindMatchingRun returns the first spot ID of the lowest run of length adjacent vacant spots
+// that all satisfy req.Require; req.Prefer only matters in that accessible spots are skipped
+// unless the request asks for them. Callers wanting preferences should call again with req.Preferred()
+func (sc *SpotCollection) FindMatchingRun(length int, req SpotRequirements) (int, error) {
+	if length < 1 {
+		length = 1
 	}
 
-	spot.Occupied = false
-	spot.Vehicle = nil
-	return nil
-}
+	sc.mu.RLock()
+	defer sc.mu.RUnlock()
 
-func (m *MotorCycleSpot) IsOccupied(spotID int) bool {
-	m.mu.RLock()
-	defer m.mu.RUnlock()
-
-	if spotID < 1 || spotID > len(m.spots) {
-		return false
+	if req.Require == 0 && req.excluded()&sc.presentAttributes() == 0 {
+		index := sc.vacant.firstRun(length)
+		if index < 0 || index+length > len(sc.spots) {
+			return 0, ErrNoVacantSpot
+		}
+		return index + 1, nil
 	}
-	return m.spots[spotID-1].Occupied
+	return sc.scanMatchingRun(0, length, req)
 }
 
-func (m *MotorCycleSpot) GetVehicle(spotID int) (Vehicle, error) {
-	m.mu.RLock()
-	defer m.mu.RUnlock()
-
-	if spotID < 1 || spotID > len(m.spots) {
-		return nil, ErrSpotNotFound
+// FindMatchingRunAfter is FindMatchingRun limited to runs whose first spot ID is greater than after
+// Callers use it to step past runs they have ruled out for their own reasons
+func (sc *SpotCollection) FindMatchingRunAfter(after, length int, req SpotRequirements) (int, error) {
+	if length < 1 {
+		length = 1
 	}
-
-	spot := m.spots[spotID-1]
-	if !spot.Occupied {
-		return nil, ErrSpotAlreadyVacant
+	if after < 0 {
+		after = 0
 	}
 
-	return spot.Vehicle, nil
-}
+	sc.mu.RLock()
+	defer sc.mu.RUnlock()
 
-func (m *MotorCycleSpot) GetTotalSpots() int {
-	m.mu.RLock()
-	defer m.mu.RUnlock()
-	return len(m.spots)
+	return sc.scanMatchingRun(after, length, req)
 }
 
-func (m *MotorCycleSpot) GetOccupiedCount() int {
-	m.mu.RLock()
-	defer m.mu.RUnlock()
+// scanMatchingRun walks the vacant spots from index from, returning the first spot ID of a matching run
+func (sc *SpotCollection) scanMatchingRun(from, length int, req SpotRequirements) (int, error) {
+	require := req.Require
+	exclude := req.excluded() & sc.presentAttributes()
+	checkCharger := require.Has(EVCharger) && req.MinChargerKW > 0
 
-	count := 0
-	for _, spot := range m.spots {
-		if spot.Occupied {
-			count++
+	run := 0
+	for w := from / 64; w < len(sc.vacant.words); w++ {
+		word := sc.matchingWord(w, require, exclude)
+		if word == 0 {
+			run = 0
+			continue
+		}
+		for bit := 0; bit < 64; bit++ {
+			index := w*64 + bit
+			if index >= len(sc.spots) {
+				break
+			}
+			if index < from || word&(uint64(1)<<bit) == 0 || (checkCharger && sc.spots[index].ChargerKW < req.MinChargerKW) {
+				run = 0
+				continue
+			}
+			run++
+			if run == length {
+				return index - length + 2, nil
+			}
 		}
 	}
-	return count
-}
-
-func (m *MotorCycleSpot) GetVacantCount() int {
-	return m.GetTotalSpots() - m.GetOccupiedCount()
-}
-
-// CarSpot manages multiple car parking spots
-type CarSpot struct {
-	spots []*Spot
-	mu    sync.RWMutex
+	return 0, ErrNoVacantSpot
 }
 
-// NewCarSpot creates a new CarSpot with specified capacity
-func NewCarSpot(capacity int) *CarSpot {
-	spots := make([]*Spot, capacity)
-	for i := 0; i < capacity; i++ {
-		spots[i] = &Spot{ID: i + 1, Occupied: false}
+// matchingWord returns the vacant spots in word w that have every required attribute and no excluded one
+func (sc *SpotCollection) matchingWord(w int, require, exclude SpotAttributes) uint64 {
+	word := sc.vacant.words[w]
+	for _, attribute := range AllSpotAttributes() {
+		switch {
+		case require.Has(attribute):
+			spots, ok := sc.attributes[attribute]
+			if !ok {
+				return 0
+			}
+			word &= spots.words[w]
+		case exclude.Has(attribute):
+			word &^= sc.attributes[attribute].words[w]
+		}
 	}
-	return &CarSpot{spots: spots}
+	return word
 }
 
-func (c *CarSpot) FindVacantSpot() (int, error) {
-	c.mu.Lock()
-	defer c.mu.Unlock()
-
-	for _, spot := range c.spots {
-		if !spot.Occupied {
-			return spot.ID, nil
+// presentAttributes returns the attributes offered by at least one spot in the collection
+func (sc *SpotCollection) presentAttributes() SpotAttributes {
+	var present SpotAttributes
+	for attribute, spots := range sc.attributes {
+		if spots.len() > 0 {
+			present |= attribute
 		}
 	}
-	return 0, ErrNoVacantSpot
+	return present
 }
 
-func (c *CarSpot) OccupySpot(spotID int, vehicle Vehicle) error {
-	c.mu.Lock()
-	defer c.mu.Unlock()
+// SetSpotAttributes replaces the attributes of a spot
+// chargerKW is the charger power rating and is ignored unless the attributes include EVCharger
+func (sc *SpotCollection) SetSpotAttributes(spotID int, attributes SpotAttributes, chargerKW float64) error {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
 
-	if spotID < 1 || spotID > len(c.spots) {
+	if spotID < 1 || spotID > len(sc.spots) {
 		return ErrSpotNotFound
 	}
 
-	spot := c.spots[spotID-1]
-	if spot.Occupied {
-		return ErrSpotAlreadyOccupied
+	spot := sc.spots[spotID-1]
+	spot.Attributes = attributes
+	spot.ChargerKW = 0
+	if attributes.Has(EVCharger) {
+		spot.ChargerKW = chargerKW
 	}
 
-	spot.Occupied = true
-	spot.Vehicle = vehicle
+	for _, attribute := range AllSpotAttributes() {
+		spots, ok := sc.attributes[attribute]
+		if !ok {
+			if !attributes.Has(attribute) {
+				continue
+			}
+			spots = newSpotBitmap(len(sc.spots))
+			sc.attributes[attribute] = spots
+		}
+		if attributes.Has(attribute) {
+			spots.set(spotID - 1)
+		} else {
+			spots.clear(spotID - 1)
+		}
+	}
 	return nil
 }
 
-func (c *CarSpot) ReleaseSpot(spotID int) error {
-	c.mu.Lock()
-	defer c.mu.Unlock()
+// GetSpot returns a copy of a spot
+func (sc *SpotCollection) GetSpot(spotID int) (Spot, error) {
+	sc.mu.RLock()
+	defer sc.mu.RUnlock()
 
-	if spotID < 1 || spotID > len(c.spots) {
-		return ErrSpotNotFound
+	if spotID < 1 || spotID > len(sc.spots) {
+		return Spot{}, ErrSpotNotFound
 	}
+	return *sc.spots[spotID-1], nil
+}
 
-	spot := c.spots[spotID-1]
-	if !spot.Occupied {
-		return ErrSpotAlreadyVacant
-	}
+func (sc *SpotCollection) OccupySpot(spotID int, vehicle Vehicle) error {
+	return sc.OccupySpots([]int{spotID}, vehicle)
+}
 
-	spot.Occupied = false
-	spot.Vehicle = nil
+// OccupySpots binds every spot in spotIDs to the same vehicle
+// Either all spots are occupied or, if any is missing or not Available, none are
+func (sc *SpotCollection) OccupySpots(spotIDs []int, vehicle Vehicle) error {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+
+	if err := sc.checkAvailable(spotIDs); err != nil {
+		return err
+	}
+	for _, spotID := range spotIDs {
+		sc.setState(sc.spots[spotID-1], SpotOccupied)
+		sc.spots[spotID-1].Vehicle = vehicle
+	}
 	return nil
 }
 
-func (c *CarSpot) IsOccupied(spotID int) bool {
-	c.mu.RLock()
-	defer c.mu.RUnlock()
+func (sc *SpotCollection) ReleaseSpot(spotID int) error {
+	return sc.ReleaseSpots([]int{spotID})
+}
 
-	if spotID < 1 || spotID > len(c.spots) {
-		return false
+// ReleaseSpots frees every spot in spotIDs together
+// Either all spots are released or, if any is missing or not occupied, none are
+func (sc *SpotCollection) ReleaseSpots(spotIDs []int) error {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+
+	if err := sc.checkState(spotIDs, SpotOccupied, ErrSpotAlreadyVacant); err != nil {
+		return err
+	}
+	for _, spotID := range spotIDs {
+		sc.free(spotID)
 	}
-	return c.spots[spotID-1].Occupied
+	return nil
 }
 
-func (c *CarSpot) GetVehicle(spotID int) (Vehicle, error) {
-	c.mu.RLock()
-	defer c.mu.RUnlock()
+// ReserveSpots holds every spot in spotIDs for a reservation, taking them out of the vacant pool
+// Either all spots are reserved or, if any is missing or not Available, none are
+func (sc *SpotCollection) ReserveSpots(spotIDs []int) error {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
 
-	if spotID < 1 || spotID > len(c.spots) {
-		return nil, ErrSpotNotFound
+	if err := sc.checkAvailable(spotIDs); err != nil {
+		return err
 	}
-
-	spot := c.spots[spotID-1]
-	if !spot.Occupied {
-		return nil, ErrSpotAlreadyVacant
+	for _, spotID := range spotIDs {
+		sc.setState(sc.spots[spotID-1], SpotReserved)
 	}
-
-	return spot.Vehicle, nil
+	return nil
 }
 
-func (c *CarSpot) GetTotalSpots() int {
-	c.mu.RLock()
-	defer c.mu.RUnlock()
-	return len(c.spots)
+// UnreserveSpots returns every spot in spotIDs to the vacant pool
+// Either all spots are unreserved or, if any is missing or not reserved, none are
+func (sc *SpotCollection) UnreserveSpots(spotIDs []int) error {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+
+	if err := sc.checkState(spotIDs, SpotReserved, ErrSpotNotReserved); err != nil {
+		return err
+	}
+	for _, spotID := range spotIDs {
+		sc.free(spotID)
+	}
+	return nil
 }
 
-func (c *CarSpot) GetOccupiedCount() int {
-	c.mu.RLock()
-	defer c.mu.RUnlock()
+// SetSpotsState moves every spot in spotIDs to an operator-controlled state
+// state is Available, OutOfService or Blocked; vehicles and reservations have their own methods
+// Either all spots move or, if any is missing or the transition isn't allowed, none do
+func (sc *SpotCollection) SetSpotsState(spotIDs []int, state SpotState) error {
+	if state != SpotAvailable && state != SpotOutOfService && state != SpotBlocked {
+		return fmt.Errorf("%w: spots can't be set to %s directly", ErrInvalidSpotTransition, state)
+	}
+
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
 
-	count := 0
-	for _, spot := range c.spots {
-		if spot.Occupied {
-			count++
+	for _, spotID := range spotIDs {
+		if spotID < 1 || spotID > len(sc.spots) {
+			return ErrSpotNotFound
+		}
+		current := sc.spots[spotID-1].State
+		if current == SpotOccupied || current == SpotReserved {
+			return fmt.Errorf("%w: spot %d is %s", ErrInvalidSpotTransition, spotID, current)
+		}
+		if current != state && !current.CanTransitionTo(state) {
+			return fmt.Errorf("%w: spot %d from %s to %s", ErrInvalidSpotTransition, spotID, current, state)
 		}
 	}
-	return count
-}
 
-func (c *CarSpot) GetVacantCount() int {
-	return c.GetTotalSpots() - c.GetOccupiedCount()
+	for _, spotID := range spotIDs {
+		// An explicit operator decision outlives the closure of the collection
+		sc.closedOut.clear(spotID - 1)
+		target := state
+		if target == SpotAvailable && sc.closed {
+			target = SpotOutOfService
+			sc.closedOut.set(spotID - 1)
+		}
+		sc.setState(sc.spots[spotID-1], target)
+	}
+	return nil
 }
 
-// TruckSpot manages multiple truck parking spots
-type TruckSpot struct {
-	spots []*Spot
-	mu    sync.RWMutex
-}
+// SetClosed closes or reopens the collection
+// Closing takes every Available spot out of service, and spots freed while closed follow;
+// reopening returns those spots to Available. Spots an operator set out of service or blocked stay so
+func (sc *SpotCollection) SetClosed(closed bool) {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
 
-// NewTruckSpot creates a new TruckSpot with specified capacity
-func NewTruckSpot(capacity int) *TruckSpot {
-	spots := make([]*Spot, capacity)
-	for i := 0; i < capacity; i++ {
-		spots[i] = &Spot{ID: i + 1, Occupied: false}
+	if sc.closed == closed {
+		return
+	}
+	sc.closed = closed
+
+	for i, spot := range sc.spots {
+		switch {
+		case closed && spot.State == SpotAvailable:
+			sc.setState(spot, SpotOutOfService)
+			sc.closedOut.set(i)
+		case !closed && sc.closedOut.test(i):
+			sc.setState(spot, SpotAvailable)
+			sc.closedOut.clear(i)
+		}
 	}
-	return &TruckSpot{spots: spots}
 }
 
-func (t *TruckSpot) FindVacantSpot() (int, error) {
-	t.mu.Lock()
-	defer t.mu.Unlock()
+// ClosedOut reports whether a spot is out of service only because the collection is closed,
+// meaning it returns to Available when the collection reopens
+func (sc *SpotCollection) ClosedOut(spotID int) bool {
+	sc.mu.RLock()
+	defer sc.mu.RUnlock()
 
-	for _, spot := range t.spots {
-		if !spot.Occupied {
-			return spot.ID, nil
-		}
+	if spotID < 1 || spotID > len(sc.spots) {
+		return false
 	}
-	return 0, ErrNoVacantSpot
+	return sc.closedOut.test(spotID - 1)
 }
 
-func (t *TruckSpot) OccupySpot(spotID int, vehicle Vehicle) error {
-	t.mu.Lock()
-	defer t.mu.Unlock()
+func (sc *SpotCollection) IsClosed() bool {
+	sc.mu.RLock()
+	defer sc.mu.RUnlock()
+	return sc.closed
+}
 
-	if spotID < 1 || spotID > len(t.spots) {
-		return ErrSpotNotFound
+// checkAvailable returns ErrSpotNotAvailable unless every spot exists and is Available
+func (sc *SpotCollection) checkAvailable(spotIDs []int) error {
+	for _, spotID := range spotIDs {
+		if spotID < 1 || spotID > len(sc.spots) {
+			return ErrSpotNotFound
+		}
+		switch state := sc.spots[spotID-1].State; state {
+		case SpotAvailable:
+		case SpotOccupied:
+			return fmt.Errorf("%w: %w", ErrSpotNotAvailable, ErrSpotAlreadyOccupied)
+		default:
+			return fmt.Errorf("%w: spot %d is %s", ErrSpotNotAvailable, spotID, state)
+		}
 	}
+	return nil
+}
 
-	spot := t.spots[spotID-1]
-	if spot.Occupied {
-		return ErrSpotAlreadyOccupied
+// checkState returns errWrongState unless every spot exists and is in the given state
+func (sc *SpotCollection) checkState(spotIDs []int, state SpotState, errWrongState error) error {
+	for _, spotID := range spotIDs {
+		if spotID < 1 || spotID > len(sc.spots) {
+			return ErrSpotNotFound
+		}
+		if sc.spots[spotID-1].State != state {
+			return errWrongState
+		}
 	}
-
-	spot.Occupied = true
-	spot.Vehicle = vehicle
 	return nil
 }
 
-func (t *TruckSpot) ReleaseSpot(spotID int) error {
-	t.mu.Lock()
-	defer t.mu.Unlock()
-
-	if spotID < 1 || spotID > len(t.spots) {
-		return ErrSpotNotFound
+// free returns a spot to Available, or takes it out of service when the collection is closed
+func (sc *SpotCollection) free(spotID int) {
+	spot := sc.spots[spotID-1]
+	spot.Vehicle = nil
+	if sc.closed {
+		sc.setState(spot, SpotOutOfService)
+		sc.closedOut.set(spotID - 1)
+		return
 	}
-
-	spot := t.spots[spotID-1]
-	if !spot.Occupied {
-		return ErrSpotAlreadyVacant
+	sc.setState(spot, SpotAvailable)
+}
+
+// setState moves a spot to a new state, keeping the vacant bitmap and counters in step
+// Callers check the transition first
+func (sc *SpotCollection) setState(spot *Spot, state SpotState) {
+	sc.counts[spot.State]--
+	sc.counts[state]++
+	spot.State = state
+	if state == SpotAvailable {
+		sc.vacant.set(spot.ID - 1)
+	} else {
+		sc.vacant.clear(spot.ID - 1)
 	}
-
-	spot.Occupied = false
-	spot.Vehicle = nil
-	return nil
 }
 
-func (t *TruckSpot) IsOccupied(spotID int) bool {
-	t.mu.RLock()
-	defer t.mu.RUnlock()
+// State returns the state of a spot, or Available for unknown spot IDs
+func (sc *SpotCollection) State(spotID int) SpotState {
+	sc.mu.RLock()
+	defer sc.mu.RUnlock()
 
-	if spotID < 1 || spotID > len(t.spots) {
-		return false
+	if spotID < 1 || spotID > len(sc.spots) {
+		return SpotAvailable
 	}
-	return t.spots[spotID-1].Occupied
+	return sc.spots[spotID-1].State
+}
+
+func (sc *SpotCollection) IsReserved(spotID int) bool {
+	return sc.State(spotID) == SpotReserved
+}
+
+func (sc *SpotCollection) IsOccupied(spotID int) bool {
+	return sc.State(spotID) == SpotOccupied
 }
 
-func (t *TruckSpot) GetVehicle(spotID int) (Vehicle, error) {
-	t.mu.RLock()
-	defer t.mu.RUnlock()
+func (sc *SpotCollection) GetVehicle(spotID int) (Vehicle, error) {
+	sc.mu.RLock()
+	defer sc.mu.RUnlock()
 
-	if spotID < 1 || spotID > len(t.spots) {
+	if spotID < 1 || spotID > len(sc.spots) {
 		return nil, ErrSpotNotFound
 	}
 
-	spot := t.spots[spotID-1]
-	if !spot.Occupied {
+	spot := sc.spots[spotID-1]
+	if spot.State != SpotOccupied {
 		return nil, ErrSpotAlreadyVacant
 	}
 
 	return spot.Vehicle, nil
 }
 
-func (t *TruckSpot) GetTotalSpots() int {
-	t.mu.RLock()
-	defer t.mu.RUnlock()
-	return len(t.spots)
+func (sc *SpotCollection) GetTotalSpots() int {
+	sc.mu.RLock()
+	defer sc.mu.RUnlock()
+	return len(sc.spots)
 }
 
-func (t *TruckSpot) GetOccupiedCount() int {
-	t.mu.RLock()
-	defer t.mu.RUnlock()
+func (sc *SpotCollection) GetOccupiedCount() int {
+	return sc.GetStateCount(SpotOccupied)
+}
 
-	count := 0
-	for _, spot := range t.spots {
-		if spot.Occupied {
-			count++
-		}
+func (sc *SpotCollection) GetVacantCount() int {
+	sc.mu.RLock()
+	defer sc.mu.RUnlock()
+	return sc.vacant.len()
+}
+
+func (sc *SpotCollection) GetReservedCount() int {
+	return sc.GetStateCount(SpotReserved)
+}
+
+// GetStateCount returns how many spots are in the given state
+func (sc *SpotCollection) GetStateCount(state SpotState) int {
+	if int(state) >= spotStateCount {
+		return 0
 	}
-	return count
+
+	sc.mu.RLock()
+	defer sc.mu.RUnlock()
+	return sc.counts[state]
 }
 
-func (t *TruckSpot) GetVacantCount() int {
-	return t.GetTotalSpots() - t.GetOccupiedCount()
+// AttributeCounts returns, for each attribute offered by at least one spot, how many spots have it and how many of those are vacant
+func (sc *SpotCollection) AttributeCounts() map[SpotAttributes]AttributeCount {
+	sc.mu.RLock()
+	defer sc.mu.RUnlock()
+
+	counts := make(map[SpotAttributes]AttributeCount, len(sc.attributes))
+	for attribute, spots := range sc.attributes {
+		if spots.len() == 0 {
+			continue
+		}
+		counts[attribute] = AttributeCount{
+			Total:  spots.len(),
+			Vacant: spots.countAnd(sc.vacant),
+		}
+	}
+	return counts
 }
@@ -0,0 +1,95 @@
+package entities
+
+import (
+	"fmt"
+	"testing"
+)
+
+// benchmarkCapacities are spot counts from a single small floor to the largest garages
+var benchmarkCapacities = []int{100, 1_000, 10_000, 100_000}
+
+// newFilledCollection creates a car collection with all but its last tenth of spots occupied,
+// so the vacant spots sit behind the occupied ones like they would in a busy garage
+func newFilledCollection(b *testing.B, capacity int) *SpotCollection {
+	b.Helper()
+	sc := NewSpotCollection(CAR, capacity)
+	for spotID := 1; spotID <= capacity*9/10; spotID++ {
+		if err := sc.OccupySpot(spotID, NewCar(fmt.Sprintf("CAR-%d", spotID))); err != nil {
+			b.Fatal(err)
+		}
+	}
+	return sc
+}
+
+func BenchmarkFindVacantSpot(b *testing.B) {
+	for _, capacity := range benchmarkCapacities {
+		b.Run(fmt.Sprintf("spots=%d", capacity), func(b *testing.B) {
+			sc := newFilledCollection(b, capacity)
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if _, err := sc.FindVacantSpot(); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkOccupyReleaseSpot(b *testing.B) {
+	for _, capacity := range benchmarkCapacities {
+		b.Run(fmt.Sprintf("spots=%d", capacity), func(b *testing.B) {
+			sc := newFilledCollection(b, capacity)
+			car := NewCar("BENCH")
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				spotID, err := sc.FindVacantSpot()
+				if err != nil {
+					b.Fatal(err)
+				}
+				if err := sc.OccupySpot(spotID, car); err != nil {
+					b.Fatal(err)
+				}
+				if err := sc.ReleaseSpot(spotID); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkSpotCounts(b *testing.B) {
+	for _, capacity := range benchmarkCapacities {
+		b.Run(fmt.Sprintf("spots=%d", capacity), func(b *testing.B) {
+			sc := newFilledCollection(b, capacity)
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if sc.GetOccupiedCount()+sc.GetVacantCount() != capacity {
+					b.Fatal("counts do not add up to the capacity")
+				}
+			}
+		})
+	}
+}
+
+func TestSetSpotsStateWhileClosed(t *testing.T) {
+	sc := NewSpotCollection(CAR, 4)
+	if err := sc.SetSpotsState([]int{1, 2, 3}, SpotBlocked); err != nil {
+		t.Fatal(err)
+	}
+	sc.SetClosed(true)
+
+	// Spots an operator makes available on a closed floor wait out of service until it reopens
+	if err := sc.SetSpotsState([]int{1, 2, 3}, SpotAvailable); err != nil {
+		t.Fatal(err)
+	}
+	for spotID := 1; spotID <= 3; spotID++ {
+		if state := sc.State(spotID); state != SpotOutOfService || !sc.ClosedOut(spotID) {
+			t.Errorf("spot %d is %s (closed out %v) while closed, want out of service until reopening", spotID, state, sc.ClosedOut(spotID))
+		}
+	}
+
+	sc.SetClosed(false)
+	if vacant := sc.GetVacantCount(); vacant != 4 {
+		t.Errorf("%d spots vacant after reopening, want 4", vacant)
+	}
+}
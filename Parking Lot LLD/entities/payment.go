@@ -0,0 +1,60 @@
+package entities
+
+import "time"
+
+// PaymentMethod is how a driver pays for a ticket
+type PaymentMethod string
+
+const (
+	PaymentCash   PaymentMethod = "cash"
+	PaymentCard   PaymentMethod = "card"
+	PaymentWallet PaymentMethod = "wallet"
+)
+
+// PaymentStatus is the outcome of a payment attempt
+type PaymentStatus string
+
+const (
+	PaymentPending   PaymentStatus = "pending"
+	PaymentCompleted PaymentStatus = "completed"
+	PaymentFailed    PaymentStatus = "failed"
+)
+
+// Payment is one attempt to pay all or part of a ticket
+// Failed attempts are kept so retries can be traced; only completed payments count toward the ticket
+type Payment struct {
+	ID            string
+	TicketID      string
+	Amount        int // Amount in cents
+	Method        PaymentMethod
+	Status        PaymentStatus
+	Reference     string // Processor reference, e.g. a card authorization code
+	FailureReason string // Why the attempt failed, empty unless Status is PaymentFailed
+	Attempt       int    // 1 for the first attempt on a ticket, 2 for the next and so on
+	CreatedAt     time.Time
+}
+
+// NewPayment creates a pending payment for a ticket
+func NewPayment(ticketID string, amount int, method PaymentMethod, attempt int, createdAt time.Time) *Payment {
+	return &Payment{
+		ID:        "PAY-" + createdAt.Format("20060102150405") + "-" + randomString(6),
+		TicketID:  ticketID,
+		Amount:    amount,
+		Method:    method,
+		Status:    PaymentPending,
+		Attempt:   attempt,
+		CreatedAt: createdAt,
+	}
+}
+
+// Complete marks the payment as successful
+func (p *Payment) Complete(reference string) {
+	p.Status = PaymentCompleted
+	p.Reference = reference
+}
+
+// Fail marks the payment as failed with a reason
+func (p *Payment) Fail(reason string) {
+	p.Status = PaymentFailed
+	p.FailureReason = reason
+}
@@ -0,0 +1,72 @@
+package entities
+
+import "time"
+
+// ReservationStatus tracks a reservation from booking to arrival or release
+type ReservationStatus string
+
+const (
+	ReservationBooked    ReservationStatus = "booked"    // Window not started yet; the spot is still open to walk-ins
+	ReservationHolding   ReservationStatus = "holding"   // Window started; the spot is held for the vehicle
+	ReservationFulfilled ReservationStatus = "fulfilled" // Vehicle arrived and was issued a ticket
+	ReservationNoShow    ReservationStatus = "no-show"   // Vehicle did not arrive within the grace period
+	ReservationCancelled ReservationStatus = "cancelled"
+)
+
+// Reservation books adjacent spots of one kind on a floor for a time window
+// Drivers confirm arrival with the reservation code or their number plate
+type Reservation struct {
+	ID           string
+	Code         string // Short code given to the driver
+	NumberPlate  string
+	VehicleType  VehicleType
+	Spots        int // Number of adjacent spots booked
+	Requirements SpotRequirements
+	FloorID      int
+	SpotType     VehicleType
+	SpotIDs      []int
+	Start        time.Time // Earliest arrival; the spot is held from here on
+	End          time.Time // Expected departure
+	Status       ReservationStatus
+	TicketID     string // Ticket issued on arrival
+	CreatedAt    time.Time
+}
+
+// NewReservation creates a booked reservation
+func NewReservation(numberPlate string, vehicleType VehicleType, spots int, requirements SpotRequirements, start, end, createdAt time.Time) *Reservation {
+	return &Reservation{
+		ID:           "RES-" + createdAt.Format("20060102150405") + "-" + randomString(6),
+		Code:         randomString(8),
+		NumberPlate:  numberPlate,
+		VehicleType:  vehicleType,
+		Spots:        spots,
+		Requirements: requirements,
+		Start:        start,
+		End:          end,
+		Status:       ReservationBooked,
+		CreatedAt:    createdAt,
+	}
+}
+
+// IsOpen returns true while the reservation can still be fulfilled
+func (r *Reservation) IsOpen() bool {
+	return r.Status == ReservationBooked || r.Status == ReservationHolding
+}
+
+// Overlaps reports whether the reservation's window overlaps [start, end)
+func (r *Reservation) Overlaps(start, end time.Time) bool {
+	return r.Start.Before(end) && start.Before(r.End)
+}
+
+// Holds reports whether the reservation covers a spot on a floor
+func (r *Reservation) Holds(floorID int, spotType VehicleType, spotID int) bool {
+	if r.FloorID != floorID || r.SpotType != spotType {
+		return false
+	}
+	for _, id := range r.SpotIDs {
+		if id == spotID {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,139 @@
+package entities
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SpotAttributes is a set of features a parking spot offers, stored as bit flags
+type SpotAttributes uint8
+
+const (
+	EVCharger  SpotAttributes = 1 << iota // Spot has an EV charger (see Spot.ChargerKW for its power)
+	Accessible                            // Spot is reserved for accessible-permit holders
+	Covered                               // Spot is under a roof
+	Compact                               // Spot is narrower than standard
+	Oversized                             // Spot is larger than standard
+)
+
+// exclusiveAttributes are attributes that keep a spot for vehicles that ask for them
+const exclusiveAttributes = Accessible
+
+// spotAttributeNames lists every attribute in bit order with its display name
+var spotAttributeNames = []struct {
+	attribute SpotAttributes
+	name      string
+}{
+	{EVCharger, "ev-charger"},
+	{Accessible, "accessible"},
+	{Covered, "covered"},
+	{Compact, "compact"},
+	{Oversized, "oversized"},
+}
+
+// AllSpotAttributes returns every single attribute in bit order
+func AllSpotAttributes() []SpotAttributes {
+	attributes := make([]SpotAttributes, len(spotAttributeNames))
+	for i, entry := range spotAttributeNames {
+		attributes[i] = entry.attribute
+	}
+	return attributes
+}
+
+// Has reports whether every attribute in other is present
+func (a SpotAttributes) Has(other SpotAttributes) bool {
+	return a&other == other
+}
+
+// String returns the attribute names joined with "+", or "none"
+func (a SpotAttributes) String() string {
+	if a == 0 {
+		return "none"
+	}
+	names := make([]string, 0, len(spotAttributeNames))
+	for _, entry := range spotAttributeNames {
+		if a.Has(entry.attribute) {
+			names = append(names, entry.name)
+		}
+	}
+	return strings.Join(names, "+")
+}
+
+// ParseSpotAttributes parses a list of attribute names such as "ev-charger" or "covered"
+func ParseSpotAttributes(names ...string) (SpotAttributes, error) {
+	var attributes SpotAttributes
+	for _, name := range names {
+		name = strings.ToLower(strings.TrimSpace(name))
+		found := false
+		for _, entry := range spotAttributeNames {
+			if entry.name == name {
+				attributes |= entry.attribute
+				found = true
+				break
+			}
+		}
+		if !found {
+			return 0, fmt.Errorf("unknown spot attribute %q", name)
+		}
+	}
+	return attributes, nil
+}
+
+// SpotRequirements describes which spot attributes a vehicle or park request needs
+type SpotRequirements struct {
+	Require      SpotAttributes // Spot must have all of these
+	Prefer       SpotAttributes // Spot should have these if one is free, otherwise any spot meeting Require is used
+	MinChargerKW float64        // Minimum charger power when EVCharger is required or preferred
+}
+
+// Merge combines two sets of requirements, keeping the stricter charger power
+func (r SpotRequirements) Merge(other SpotRequirements) SpotRequirements {
+	merged := SpotRequirements{
+		Require:      r.Require | other.Require,
+		Prefer:       r.Prefer | other.Prefer,
+		MinChargerKW: r.MinChargerKW,
+	}
+	if other.MinChargerKW > merged.MinChargerKW {
+		merged.MinChargerKW = other.MinChargerKW
+	}
+	return merged
+}
+
+// Preferred returns the requirements for a first pass that also insists on the preferred attributes
+func (r SpotRequirements) Preferred() SpotRequirements {
+	return SpotRequirements{
+		Require:      r.Require | r.Prefer,
+		Prefer:       r.Prefer,
+		MinChargerKW: r.MinChargerKW,
+	}
+}
+
+// excluded returns the exclusive attributes a spot must not have for these requirements
+func (r SpotRequirements) excluded() SpotAttributes {
+	return exclusiveAttributes &^ (r.Require | r.Prefer)
+}
+
+// AttributeCount holds the number of spots offering an attribute and how many of them are vacant
+type AttributeCount struct {
+	Total  int
+	Vacant int
+}
+
+// MarshalText encodes the attributes as their names joined with "+", or "none"
+func (a SpotAttributes) MarshalText() ([]byte, error) {
+	return []byte(a.String()), nil
+}
+
+// UnmarshalText decodes attributes written by MarshalText
+func (a *SpotAttributes) UnmarshalText(text []byte) error {
+	if string(text) == "none" || len(text) == 0 {
+		*a = 0
+		return nil
+	}
+	attributes, err := ParseSpotAttributes(strings.Split(string(text), "+")...)
+	if err != nil {
+		return err
+	}
+	*a = attributes
+	return nil
+}
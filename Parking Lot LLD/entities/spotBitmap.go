@@ -0,0 +1,113 @@
+package entities
+
+import "math/bits"
+
+// spotBitmap tracks a set of spot indexes with one bit per spot
+// A second level summary keeps one bit per word so the lowest set bit
+// can be found without scanning every word, which keeps lookups flat for large floors
+type spotBitmap struct {
+	words   []uint64 // bit i is set when index i is in the set
+	summary []uint64 // bit w is set when words[w] has any bit set
+	count   int      // number of set bits
+}
+
+// newSpotBitmap creates a bitmap able to hold size indexes, with every index cleared
+func newSpotBitmap(size int) *spotBitmap {
+	wordCount := (size + 63) / 64
+	return &spotBitmap{
+		words:   make([]uint64, wordCount),
+		summary: make([]uint64, (wordCount+63)/64),
+	}
+}
+
+// set adds index i to the set
+func (b *spotBitmap) set(i int) {
+	w := i / 64
+	mask := uint64(1) << (i % 64)
+	if b.words[w]&mask != 0 {
+		return
+	}
+	b.words[w] |= mask
+	b.summary[w/64] |= uint64(1) << (w % 64)
+	b.count++
+}
+
+// clear removes index i from the set
+func (b *spotBitmap) clear(i int) {
+	w := i / 64
+	mask := uint64(1) << (i % 64)
+	if b.words[w]&mask == 0 {
+		return
+	}
+	b.words[w] &^= mask
+	if b.words[w] == 0 {
+		b.summary[w/64] &^= uint64(1) << (w % 64)
+	}
+	b.count--
+}
+
+// test reports whether index i is in the set
+func (b *spotBitmap) test(i int) bool {
+	return b.words[i/64]&(uint64(1)<<(i%64)) != 0
+}
+
+// first returns the lowest index in the set, or -1 if the set is empty
+func (b *spotBitmap) first() int {
+	return b.next(0)
+}
+
+// next returns the lowest index in the set that is >= i, or -1 if there is none
+func (b *spotBitmap) next(i int) int {
+	w := i / 64
+	if w >= len(b.words) {
+		return -1
+	}
+	if word := b.words[w] >> (i % 64); word != 0 {
+		return i + bits.TrailingZeros64(word)
+	}
+
+	// Use the summary to skip straight to the next non-empty word
+	w++
+	for s := w / 64; s < len(b.summary); s++ {
+		summaryWord := b.summary[s]
+		if s == w/64 {
+			summaryWord &= ^uint64(0) << (w % 64)
+		}
+		if summaryWord == 0 {
+			continue
+		}
+		found := s*64 + bits.TrailingZeros64(summaryWord)
+		return found*64 + bits.TrailingZeros64(b.words[found])
+	}
+	return -1
+}
+
+// firstRun returns the lowest index starting length consecutive set indexes, or -1 if there is none
+func (b *spotBitmap) firstRun(length int) int {
+	start := b.first()
+	for start >= 0 {
+		end := start + 1
+		for end < start+length && end < len(b.words)*64 && b.test(end) {
+			end++
+		}
+		if end == start+length {
+			return start
+		}
+		start = b.next(end)
+	}
+	return -1
+}
+
+// countAnd returns how many indexes are in both b and other
+func (b *spotBitmap) countAnd(other *spotBitmap) int {
+	count := 0
+	for w, word := range b.words {
+		count += bits.OnesCount64(word & other.words[w])
+	}
+	return count
+}
+
+// len returns the number of indexes in the set
+func (b *spotBitmap) len() int {
+	return b.count
+}
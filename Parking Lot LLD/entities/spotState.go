@@ -0,0 +1,89 @@
+package entities
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	ErrSpotNotAvailable      = errors.New("spot is not available")
+	ErrInvalidSpotTransition = errors.New("invalid spot state transition")
+	ErrInvalidSpotState      = errors.New("invalid spot state")
+)
+
+// SpotState is the lifecycle state of a parking spot
+// Only Available spots can take a vehicle or a reservation
+type SpotState uint8
+
+const (
+	SpotAvailable    SpotState = iota
+	SpotOccupied               // A vehicle is parked in the spot
+	SpotReserved               // Held for a reservation whose window has started
+	SpotOutOfService           // Closed by an operator, e.g. for cleaning or repairs
+	SpotBlocked                // Temporarily unusable, e.g. obstructed or coned off
+)
+
+// spotStateCount is the number of spot states, for per-state counters
+const spotStateCount = int(SpotBlocked) + 1
+
+var spotStateNames = [spotStateCount]string{"available", "occupied", "reserved", "out-of-service", "blocked"}
+
+// spotTransitions lists the states each state may move to
+// Occupied and Reserved spots are only freed by their vehicle or reservation, going out of service
+// instead of back to Available when their floor is closed; operators can't move them directly
+var spotTransitions = map[SpotState][]SpotState{
+	SpotAvailable:    {SpotOccupied, SpotReserved, SpotOutOfService, SpotBlocked},
+	SpotOccupied:     {SpotAvailable, SpotOutOfService},
+	SpotReserved:     {SpotAvailable, SpotOutOfService},
+	SpotOutOfService: {SpotAvailable, SpotBlocked},
+	SpotBlocked:      {SpotAvailable, SpotOutOfService},
+}
+
+// AllSpotStates returns every spot state in order
+func AllSpotStates() []SpotState {
+	return []SpotState{SpotAvailable, SpotOccupied, SpotReserved, SpotOutOfService, SpotBlocked}
+}
+
+func (s SpotState) String() string {
+	if int(s) < spotStateCount {
+		return spotStateNames[s]
+	}
+	return fmt.Sprintf("SpotState(%d)", uint8(s))
+}
+
+// CanTransitionTo reports whether a spot in state s may move to next
+func (s SpotState) CanTransitionTo(next SpotState) bool {
+	for _, allowed := range spotTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseSpotState parses a state name such as "out-of-service"
+func ParseSpotState(name string) (SpotState, error) {
+	for i, stateName := range spotStateNames {
+		if strings.EqualFold(strings.TrimSpace(name), stateName) {
+			return SpotState(i), nil
+		}
+	}
+	return 0, fmt.Errorf("%w: %q", ErrInvalidSpotState, name)
+}
+
+func (s SpotState) MarshalText() ([]byte, error) {
+	if int(s) >= spotStateCount {
+		return nil, fmt.Errorf("%w: %d", ErrInvalidSpotState, uint8(s))
+	}
+	return []byte(s.String()), nil
+}
+
+func (s *SpotState) UnmarshalText(text []byte) error {
+	state, err := ParseSpotState(string(text))
+	if err != nil {
+		return err
+	}
+	*s = state
+	return nil
+}
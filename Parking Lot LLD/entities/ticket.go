@@ -7,79 +7,99 @@ import (
 
 // Ticket represents a parking ticket issued to a vehicle
 type Ticket struct {
-	ID           string    // Unique ticket ID
-	Vehicle      Vehicle   // Vehicle that parked
-	EntryTime    time.Time // When vehicle entered
-	ExitTime     time.Time // When vehicle exited (zero if still parked)
-	FloorID      int       // Which floor
-	SpotID       int       // Which spot on the floor
-	VehicleType  VehicleType
-	PricePerHour int // Price per hour for this vehicle type
+	ID             string      // Unique ticket ID
+	Vehicle        Vehicle     // Vehicle that parked
+	EntryTime      time.Time   // When vehicle entered
+	ExitTime       time.Time   // When vehicle exited (zero if still parked)
+	FloorID        int         // Which floor
+	SpotID         int         // Which spot on the floor (first spot for multi-spot vehicles)
+	SpotIDs        []int       // Every spot held by this ticket
+	SpotType       VehicleType // Kind of spot the vehicle occupies
+	VehicleType    VehicleType
+	Fallback       bool      // True when the vehicle was placed in a spot of another kind
+	PricePerHour   int       // Price per hour charged for this stay, locked in at entry
+	RateMultiplier float64   // Occupancy multiplier applied to the base rate at entry (1 without dynamic pricing)
+	PolicyVersion  string    // Version of the pricing policy the ticket was issued under
+	Fee            int       // Final price, set when the vehicle exits
+	PaidAmount     int       // Total of completed payments
+	PaidAt         time.Time // When the fee due was last paid in full (zero if not)
+	Penalty        int       // Lost-ticket fee added on top of the stay's price
+	ReplacesID     string    // ID of the lost ticket this one replaces (empty if none)
+	VoidedAt       time.Time // When the ticket was voided (zero if not)
+	ReplacedByID   string    // ID of the ticket issued in place of this one once voided
+	ReservationID  string    // Reservation the vehicle arrived on (empty for walk-ins)
+	EntryGateID    string    // Gate the vehicle came in through (empty if not parked at a gate)
+	ExitGateID     string    // Gate the vehicle left through (empty if not released at a gate)
 }
 
 // NewTicket creates a new parking ticket
-func NewTicket(vehicle Vehicle, floorID, spotID int, pricePerHour int) *Ticket {
+// spotType is the kind of spot occupied, which differs from the vehicle type for fallback placements
+// spotIDs lists every spot held, in order; oversized vehicles hold more than one
+// entryTime comes from the caller's clock
+func NewTicket(vehicle Vehicle, floorID int, spotType VehicleType, spotIDs []int, pricePerHour int, entryTime time.Time) *Ticket {
 	return &Ticket{
-		ID:           generateTicketID(),
-		Vehicle:      vehicle,
-		EntryTime:    time.Now(),
-		FloorID:      floorID,
-		SpotID:       spotID,
-		VehicleType:  vehicle.Type(),
-		PricePerHour: pricePerHour,
+		ID:             generateTicketID(entryTime),
+		Vehicle:        vehicle,
+		EntryTime:      entryTime,
+		FloorID:        floorID,
+		SpotID:         spotIDs[0],
+		SpotIDs:        spotIDs,
+		SpotType:       spotType,
+		VehicleType:    vehicle.Type(),
+		Fallback:       IsFallbackSpot(vehicle.Type(), spotType),
+		PricePerHour:   pricePerHour,
+		RateMultiplier: 1,
 	}
 }
 
-// CalculatePrice calculates the total parking fee based on duration
-// Returns price in cents or smallest currency unit
-func (t *Ticket) CalculatePrice() int {
-	if t.ExitTime.IsZero() {
-		// Vehicle is still parked, calculate based on current time
-		duration := time.Since(t.EntryTime)
-		hours := int(duration.Hours())
-		if duration.Minutes() > float64(hours*60) {
-			hours++ // Round up to next hour
-		}
-		if hours == 0 {
-			hours = 1 // Minimum 1 hour charge
-		}
-		return hours * t.PricePerHour
-	}
-
-	// Vehicle has exited, calculate based on exit time
-	duration := t.ExitTime.Sub(t.EntryTime)
-	hours := int(duration.Hours())
-	if duration.Minutes() > float64(hours*60) {
-		hours++ // Round up to next hour
-	}
-	if hours == 0 {
-		hours = 1 // Minimum 1 hour charge
-	}
-	return hours * t.PricePerHour
-}
-
 // GetDuration returns the parking duration
-func (t *Ticket) GetDuration() time.Duration {
+// For a vehicle still parked the duration runs until now
+func (t *Ticket) GetDuration(now time.Time) time.Duration {
 	if t.ExitTime.IsZero() {
-		return time.Since(t.EntryTime)
+		return now.Sub(t.EntryTime)
 	}
 	return t.ExitTime.Sub(t.EntryTime)
 }
 
 // MarkExit records the exit time
-func (t *Ticket) MarkExit() {
-	t.ExitTime = time.Now()
+func (t *Ticket) MarkExit(exitTime time.Time) {
+	t.ExitTime = exitTime
 }
 
-// IsActive returns true if vehicle is still parked
+// IsActive returns true if vehicle is still parked under this ticket
 func (t *Ticket) IsActive() bool {
-	return t.ExitTime.IsZero()
+	return t.ExitTime.IsZero() && !t.IsVoided()
+}
+
+// IsVoided returns true if the ticket was voided, e.g. after being reported lost
+func (t *Ticket) IsVoided() bool {
+	return !t.VoidedAt.IsZero()
+}
+
+// Copy returns a copy of the ticket that can be read or changed without affecting the original
+func (t *Ticket) Copy() *Ticket {
+	copied := *t
+	copied.SpotIDs = append([]int(nil), t.SpotIDs...)
+	return &copied
+}
+
+// Replace voids the ticket and issues a replacement for the same stay
+// The replacement keeps the entry time, spots, rate and payments made so far
+func (t *Ticket) Replace(voidedAt time.Time) *Ticket {
+	replacement := *t
+	replacement.ID = generateTicketID(voidedAt)
+	replacement.SpotIDs = append([]int(nil), t.SpotIDs...)
+	replacement.ReplacesID = t.ID
+
+	t.VoidedAt = voidedAt
+	t.ReplacedByID = replacement.ID
+	return &replacement
 }
 
 // generateTicketID generates a unique ticket ID
 // In production, use UUID or database sequence
-func generateTicketID() string {
-	return time.Now().Format("20060102150405") + "-" + randomString(6)
+func generateTicketID(issuedAt time.Time) string {
+	return issuedAt.Format("20060102150405") + "-" + randomString(6)
 }
 
 // randomString generates a random string using crypto/rand
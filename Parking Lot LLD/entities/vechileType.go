@@ -1,6 +1,7 @@
 package entities
 
 // VehicleType represents the type of vehicle
+// The built-in types are listed below; more can be added at startup with RegisterVehicleType
 type VehicleType int
 
 const (
@@ -15,6 +16,27 @@ type Vehicle interface {
 	GetNumberPlate() string
 }
 
+// MultiSpotVehicle is implemented by oversized vehicles that need several adjacent spots
+type MultiSpotVehicle interface {
+	Vehicle
+	SpotsRequired() int
+}
+
+// SpotsRequired returns how many adjacent spots a vehicle occupies (at least 1)
+// Vehicles that don't say otherwise use the value registered for their type
+func SpotsRequired(vehicle Vehicle) int {
+	if multi, ok := vehicle.(MultiSpotVehicle); ok {
+		if spots := multi.SpotsRequired(); spots > 1 {
+			return spots
+		}
+		return 1
+	}
+	if info, ok := LookupVehicleType(vehicle.Type()); ok {
+		return info.SpotsRequired
+	}
+	return 1
+}
+
 // MotorCycle represents a motorcycle vehicle
 type MotorCycle struct {
 	numberPlate string
@@ -54,8 +76,10 @@ func NewCar(numberPlate string) *Car {
 }
 
 // Truck represents a truck vehicle
+// A truck towing a trailer occupies several adjacent truck spots
 type Truck struct {
 	numberPlate string
+	spots       int
 }
 
 func (t Truck) Type() VehicleType {
@@ -67,7 +91,40 @@ func (t Truck) GetNumberPlate() string {
 	return t.numberPlate
 }
 
+// SpotsRequired returns the number of adjacent truck spots the truck needs
+func (t Truck) SpotsRequired() int {
+	if t.spots < 1 {
+		return 1
+	}
+	return t.spots
+}
+
 // NewTruck creates a new Truck instance
 func NewTruck(numberPlate string) *Truck {
-	return &Truck{numberPlate: numberPlate}
+	return &Truck{numberPlate: numberPlate, spots: 1}
+}
+
+// NewTruckWithTrailer creates a Truck that needs spots adjacent truck spots
+func NewTruckWithTrailer(numberPlate string, spots int) *Truck {
+	return &Truck{numberPlate: numberPlate, spots: spots}
+}
+
+// NewVehicleOfType creates a vehicle of a registered type, using the dedicated struct for
+// built-in types; spots is the number of adjacent spots a truck needs and is ignored otherwise
+func NewVehicleOfType(vehicleType VehicleType, numberPlate string, spots int) (Vehicle, error) {
+	switch vehicleType {
+	case MOTORCYCLE:
+		return NewMotorCycle(numberPlate), nil
+	case CAR:
+		return NewCar(numberPlate), nil
+	case TRUCK:
+		return NewTruckWithTrailer(numberPlate, spots), nil
+	}
+
+	// Return nil itself on error rather than a nil *BasicVehicle, which would not compare equal to nil
+	vehicle, err := NewVehicle(vehicleType, numberPlate)
+	if err != nil {
+		return nil, err
+	}
+	return vehicle, nil
 }
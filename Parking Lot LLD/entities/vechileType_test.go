@@ -0,0 +1,16 @@
+package entities
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestNewVehicleOfTypeUnregistered(t *testing.T) {
+	vehicle, err := NewVehicleOfType(VehicleType(1000), "GHOST-1", 1)
+	if !errors.Is(err, ErrInvalidVehicleType) {
+		t.Errorf("got error %v, want ErrInvalidVehicleType", err)
+	}
+	if vehicle != nil {
+		t.Errorf("got vehicle %#v along with the error, want nil", vehicle)
+	}
+}
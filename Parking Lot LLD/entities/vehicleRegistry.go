@@ -0,0 +1,212 @@
+package entities
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+var (
+	ErrVehicleTypeExists  = errors.New("vehicle type already registered")
+	ErrInvalidVehicleType = errors.New("invalid vehicle type")
+)
+
+// SizeClass orders vehicle types by how much room they need
+type SizeClass int
+
+const (
+	SizeSmall SizeClass = iota
+	SizeMedium
+	SizeLarge
+	SizeExtraLarge
+)
+
+// VehicleTypeInfo describes a vehicle type known to the registry
+type VehicleTypeInfo struct {
+	Type          VehicleType      // Assigned by the registry
+	Name          string           // Display name, e.g. "Car"
+	SizeClass     SizeClass        // How much room the vehicle needs
+	DefaultRate   int              // Price per hour in cents when the lot sets no explicit price
+	SpotKinds     []VehicleType    // Spot kinds the type parks in, in order of preference; empty means its own kind
+	SpotsRequired int              // Adjacent spots occupied; 0 or 1 means a single spot
+	Requirements  SpotRequirements // Spot attributes the type requires or prefers, e.g. an EV preferring a charger
+}
+
+// ParksIn reports whether kind is one of the spot kinds registered for the type
+// Parking anywhere else is a fallback placement
+func (info VehicleTypeInfo) ParksIn(kind VehicleType) bool {
+	if len(info.SpotKinds) == 0 {
+		return kind == info.Type
+	}
+	for _, own := range info.SpotKinds {
+		if own == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// IsFallbackSpot reports whether parking a vehicle type in a spot of the given kind is a fallback placement
+func IsFallbackSpot(vehicleType, spotType VehicleType) bool {
+	if info, ok := LookupVehicleType(vehicleType); ok {
+		return !info.ParksIn(spotType)
+	}
+	return vehicleType != spotType
+}
+
+// vehicleTypeRegistry holds every vehicle type, indexed by type and by name
+type vehicleTypeRegistry struct {
+	types  []VehicleTypeInfo
+	byName map[string]VehicleType
+	mu     sync.RWMutex
+}
+
+// vehicleTypes is the process-wide registry, seeded with the built-in types
+// Built-ins are registered in iota order so their VehicleType values stay stable
+var vehicleTypes = newVehicleTypeRegistry(
+	VehicleTypeInfo{Name: "Motorcycle", SizeClass: SizeSmall, DefaultRate: 10},
+	VehicleTypeInfo{Name: "Car", SizeClass: SizeMedium, DefaultRate: 20},
+	VehicleTypeInfo{Name: "Truck", SizeClass: SizeLarge, DefaultRate: 50},
+)
+
+func newVehicleTypeRegistry(builtins ...VehicleTypeInfo) *vehicleTypeRegistry {
+	r := &vehicleTypeRegistry{byName: make(map[string]VehicleType)}
+	for _, info := range builtins {
+		if _, err := r.register(info); err != nil {
+			panic(err)
+		}
+	}
+	return r
+}
+
+func (r *vehicleTypeRegistry) register(info VehicleTypeInfo) (VehicleType, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := strings.ToLower(strings.TrimSpace(info.Name))
+	if key == "" {
+		return 0, fmt.Errorf("%w: name is required", ErrInvalidVehicleType)
+	}
+	if _, exists := r.byName[key]; exists {
+		return 0, fmt.Errorf("%w: %s", ErrVehicleTypeExists, info.Name)
+	}
+	for _, kind := range info.SpotKinds {
+		if int(kind) < 0 || int(kind) >= len(r.types) {
+			return 0, fmt.Errorf("%w: %s parks in unknown spot kind %d", ErrInvalidVehicleType, info.Name, kind)
+		}
+	}
+
+	info.Type = VehicleType(len(r.types))
+	if info.SpotsRequired < 1 {
+		info.SpotsRequired = 1
+	}
+	r.types = append(r.types, info)
+	r.byName[key] = info.Type
+	return info.Type, nil
+}
+
+// RegisterVehicleType adds a new vehicle type and returns its assigned VehicleType
+// Types are usually registered at startup, before the parking lot service is created
+func RegisterVehicleType(info VehicleTypeInfo) (VehicleType, error) {
+	return vehicleTypes.register(info)
+}
+
+// MustRegisterVehicleType is like RegisterVehicleType but panics on error
+func MustRegisterVehicleType(info VehicleTypeInfo) VehicleType {
+	vehicleType, err := RegisterVehicleType(info)
+	if err != nil {
+		panic(err)
+	}
+	return vehicleType
+}
+
+// LookupVehicleType returns the registered information for a vehicle type
+func LookupVehicleType(vehicleType VehicleType) (VehicleTypeInfo, bool) {
+	vehicleTypes.mu.RLock()
+	defer vehicleTypes.mu.RUnlock()
+
+	if int(vehicleType) < 0 || int(vehicleType) >= len(vehicleTypes.types) {
+		return VehicleTypeInfo{}, false
+	}
+	return vehicleTypes.types[vehicleType], true
+}
+
+// VehicleTypeByName finds a registered vehicle type by its name, ignoring case
+func VehicleTypeByName(name string) (VehicleType, bool) {
+	vehicleTypes.mu.RLock()
+	defer vehicleTypes.mu.RUnlock()
+
+	vehicleType, ok := vehicleTypes.byName[strings.ToLower(strings.TrimSpace(name))]
+	return vehicleType, ok
+}
+
+// VehicleTypes returns every registered vehicle type in registration order
+func VehicleTypes() []VehicleTypeInfo {
+	vehicleTypes.mu.RLock()
+	defer vehicleTypes.mu.RUnlock()
+
+	types := make([]VehicleTypeInfo, len(vehicleTypes.types))
+	copy(types, vehicleTypes.types)
+	return types
+}
+
+// String returns the display name of the vehicle type
+func (vt VehicleType) String() string {
+	if info, ok := LookupVehicleType(vt); ok {
+		return info.Name
+	}
+	return fmt.Sprintf("VehicleType(%d)", int(vt))
+}
+
+// BasicVehicle is a vehicle of any registered type
+// Types without a dedicated struct, such as ones registered at startup, use it
+type BasicVehicle struct {
+	vehicleType VehicleType
+	numberPlate string
+}
+
+func (v BasicVehicle) Type() VehicleType {
+	return v.vehicleType
+}
+
+// GetNumberPlate returns the license plate number (exported method)
+func (v BasicVehicle) GetNumberPlate() string {
+	return v.numberPlate
+}
+
+// SpotsRequired returns the number of adjacent spots registered for the vehicle type
+func (v BasicVehicle) SpotsRequired() int {
+	if info, ok := LookupVehicleType(v.vehicleType); ok {
+		return info.SpotsRequired
+	}
+	return 1
+}
+
+// NewVehicle creates a vehicle of the given registered type
+func NewVehicle(vehicleType VehicleType, numberPlate string) (*BasicVehicle, error) {
+	if _, ok := LookupVehicleType(vehicleType); !ok {
+		return nil, fmt.Errorf("%w: %d", ErrInvalidVehicleType, int(vehicleType))
+	}
+	return &BasicVehicle{vehicleType: vehicleType, numberPlate: numberPlate}, nil
+}
+
+// MarshalText encodes the vehicle type by its registered name, so stored data survives
+// changes in registration order
+func (vt VehicleType) MarshalText() ([]byte, error) {
+	info, ok := LookupVehicleType(vt)
+	if !ok {
+		return nil, fmt.Errorf("%w: %d", ErrInvalidVehicleType, int(vt))
+	}
+	return []byte(info.Name), nil
+}
+
+// UnmarshalText decodes a vehicle type from its registered name
+func (vt *VehicleType) UnmarshalText(text []byte) error {
+	vehicleType, ok := VehicleTypeByName(string(text))
+	if !ok {
+		return fmt.Errorf("%w: %q", ErrInvalidVehicleType, string(text))
+	}
+	*vt = vehicleType
+	return nil
+}
@@ -2,13 +2,18 @@ package main
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
 	"time"
 
-	"./entities"
-	"./service"
+	"parkinglot/display"
+	"parkinglot/entities"
+	"parkinglot/gate"
+	"parkinglot/service"
 )
 
-func main() {
+// runExample walks through the lot's features with a scripted scenario
+func runExample() {
 	// Example: Create a parking lot with 3 floors
 	// Each floor configuration: [carCapacity, motorcycleCapacity, truckCapacity]
 	floorsConfig := [][3]int{
@@ -24,8 +29,18 @@ func main() {
 		entities.TRUCK:      50, // $0.50 per hour
 	}
 
-	// Create parking lot service
-	parkingLot := service.NewParkingLotService(floorsConfig, pricing)
+	// Create parking lot service with a manual clock so the example can move time forward instantly
+	clock := entities.NewManualClock(time.Now())
+	parkingLot := service.NewParkingLotService(floorsConfig, pricing, service.WithClock(clock))
+
+	// Signs at the main entrance and on floor 2, kept current from the lot's events
+	entranceBoard := display.NewEntranceBoard(parkingLot, "Main entrance")
+	floor2Board := display.NewFloorBoard(parkingLot, 2, "Floor 2")
+
+	// Print whenever a floor or the whole lot fills up or frees up
+	parkingLot.Subscribe(func(event service.Event) {
+		fmt.Printf("[event] %s at %s\n", event.Type(), event.Time().Format(time.Kitchen))
+	}, service.DeliverSync, service.EventFloorFull, service.EventFloorAvailable, service.EventLotFull, service.EventLotAvailable)
 
 	// Example 1: Park a car
 	fmt.Println("=== Example 1: Parking a Car ===")
@@ -70,12 +85,11 @@ func main() {
 	fmt.Printf("Total Active Tickets: %d\n", status.TotalActiveTickets)
 	for _, floor := range status.Floors {
 		fmt.Printf("\nFloor %d:\n", floor.FloorID)
-		fmt.Printf("  Car Spots: %d occupied, %d vacant (Total: %d)\n",
-			floor.CarSpots.Occupied, floor.CarSpots.Vacant, floor.CarSpots.Total)
-		fmt.Printf("  Motorcycle Spots: %d occupied, %d vacant (Total: %d)\n",
-			floor.MotorcycleSpots.Occupied, floor.MotorcycleSpots.Vacant, floor.MotorcycleSpots.Total)
-		fmt.Printf("  Truck Spots: %d occupied, %d vacant (Total: %d)\n",
-			floor.TruckSpots.Occupied, floor.TruckSpots.Vacant, floor.TruckSpots.Total)
+		for _, spotType := range floor.SpotKinds() {
+			spots := floor.Spots[spotType]
+			fmt.Printf("  %s Spots: %d occupied, %d vacant (Total: %d)\n",
+				spotType, spots.Occupied, spots.Vacant, spots.Total)
+		}
 	}
 
 	// Example 5: Try to park same vehicle twice (should fail)
@@ -85,23 +99,33 @@ func main() {
 		fmt.Printf("Expected error: %v\n", err)
 	}
 
-	// Example 6: Wait a bit and check current price
+	// Example 6: Let some time pass and check current price
 	fmt.Println("\n=== Example 6: Checking Current Price (Vehicle Still Parked) ===")
-	time.Sleep(2 * time.Second) // Simulate time passing
+	clock.Advance(90 * time.Minute) // Simulate time passing
 	ticket1, _ = parkingLot.GetTicket(ticket1.ID)
-	currentPrice := ticket1.CalculatePrice()
+	currentPrice, _ := parkingLot.QuotePrice(ticket1.ID)
 	fmt.Printf("Ticket %s - Current Price: %d cents (%.2f hours parked)\n",
-		ticket1.ID, currentPrice, ticket1.GetDuration().Hours())
+		ticket1.ID, currentPrice, ticket1.GetDuration(clock.Now()).Hours())
 
-	// Example 7: Unpark the car
-	fmt.Println("\n=== Example 7: Unparking the Car ===")
+	// Example 7: Pay and unpark the car
+	fmt.Println("\n=== Example 7: Paying and Unparking the Car ===")
+	if _, _, err := parkingLot.UnparkVehicle(ticket1.ID); err != nil {
+		fmt.Printf("Expected error before paying: %v\n", err)
+	}
+	payment, err := parkingLot.PayTicket(ticket1.ID, entities.PaymentCard, 0)
+	if err != nil {
+		fmt.Printf("Error paying: %v\n", err)
+	} else {
+		fmt.Printf("Paid %d cents by %s (ref %s)\n", payment.Amount, payment.Method, payment.Reference)
+	}
+	clock.Advance(5 * time.Minute) // Walk to the car, still within the exit grace period
 	finalTicket, finalPrice, err := parkingLot.UnparkVehicle(ticket1.ID)
 	if err != nil {
 		fmt.Printf("Error unparking: %v\n", err)
 	} else {
 		fmt.Printf("Vehicle unparked successfully!\n")
 		fmt.Printf("Final Price: %d cents ($%.2f)\n", finalPrice, float64(finalPrice)/100)
-		fmt.Printf("Total Duration: %s\n", finalTicket.GetDuration().Round(time.Second))
+		fmt.Printf("Total Duration: %s\n", finalTicket.GetDuration(clock.Now()).Round(time.Second))
 		fmt.Printf("Exit Time: %s\n", finalTicket.ExitTime.Format(time.RFC3339))
 	}
 
@@ -110,7 +134,7 @@ func main() {
 	status = parkingLot.GetParkingLotStatus()
 	fmt.Printf("Total Active Tickets: %d\n", status.TotalActiveTickets)
 	fmt.Printf("Floor 1 Car Spots: %d occupied, %d vacant\n",
-		status.Floors[0].CarSpots.Occupied, status.Floors[0].CarSpots.Vacant)
+		status.Floors[0].Spots[entities.CAR].Occupied, status.Floors[0].Spots[entities.CAR].Vacant)
 
 	// Example 9: Try to unpark with invalid ticket
 	fmt.Println("\n=== Example 9: Attempting to Unpark with Invalid Ticket ===")
@@ -128,5 +152,136 @@ func main() {
 		fmt.Printf("Found active ticket for XYZ-5678: %s\n", activeTicket.ID)
 		fmt.Printf("Parked at Floor %d, Spot %d\n", activeTicket.FloorID, activeTicket.SpotID)
 	}
-}
 
+	// Example 11: Driver lost their ticket
+	fmt.Println("\n=== Example 11: Lost Ticket ===")
+	replacement, err := parkingLot.ReportLostTicket("XYZ-5678", "attendant-1")
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+	} else {
+		fmt.Printf("Ticket %s voided, replacement %s issued with a %d cents penalty\n",
+			replacement.ReplacesID, replacement.ID, replacement.Penalty)
+		for _, entry := range parkingLot.AuditLog() {
+			fmt.Printf("Audit: %s %s by %s (%s)\n", entry.Action, entry.TicketID, entry.Operator, entry.Details)
+		}
+	}
+
+	// Example 12: Reserve a spot and arrive within the window
+	fmt.Println("\n=== Example 12: Reservations ===")
+	reservation, err := parkingLot.ReserveSpot(service.ReservationRequest{
+		NumberPlate: "RES-4242",
+		VehicleType: entities.CAR,
+		Start:       clock.Now().Add(30 * time.Minute),
+		End:         clock.Now().Add(3 * time.Hour),
+	})
+	if err != nil {
+		fmt.Printf("Error reserving: %v\n", err)
+	} else {
+		fmt.Printf("Reservation %s (code %s): Floor %d, Spot %d from %s\n", reservation.ID, reservation.Code,
+			reservation.FloorID, reservation.SpotIDs[0], reservation.Start.Format(time.Kitchen))
+		clock.Advance(35 * time.Minute)
+		arrival, err := parkingLot.ConfirmArrival(reservation.Code)
+		if err != nil {
+			fmt.Printf("Error confirming arrival: %v\n", err)
+		} else {
+			fmt.Printf("Arrival confirmed, ticket %s at Floor %d, Spot %d\n", arrival.ID, arrival.FloorID, arrival.SpotID)
+		}
+	}
+
+	// Example 13: Close a floor for cleaning
+	fmt.Println("\n=== Example 13: Closing a Floor ===")
+	if err := parkingLot.CloseFloor(3, "attendant-1"); err != nil {
+		fmt.Printf("Error closing floor: %v\n", err)
+	}
+	floor3 := parkingLot.GetParkingLotStatus().Floors[2]
+	fmt.Printf("Floor 3 closed: %t, Car Spots: %d out of service, %d vacant\n",
+		floor3.Closed, floor3.Spots[entities.CAR].OutOfService, floor3.Spots[entities.CAR].Vacant)
+
+	// Example 14: Display boards
+	fmt.Println("\n=== Example 14: Display Boards ===")
+	fmt.Print(entranceBoard)
+	fmt.Print(floor2Board)
+
+	// Example 15: Enter and leave through gates
+	fmt.Println("\n=== Example 15: Entry and Exit Gates ===")
+	barrier := gate.NewSimulatedBarrier(parkingLot)
+	northEntry := gate.NewEntryGate("N-IN", parkingLot, barrier)
+	southExit := gate.NewExitGate("S-OUT", parkingLot, barrier)
+	gateTicket, err := northEntry.Admit(entities.NewCar("GATE-777"), service.ParkOptions{})
+	if err != nil {
+		fmt.Printf("Error at entry gate: %v\n", err)
+	} else {
+		clock.Advance(45 * time.Minute)
+		if _, _, err := southExit.Release(gateTicket.ID); err != nil {
+			fmt.Printf("Barrier stays down: %v\n", err)
+		}
+		southExit.Pay(gateTicket.ID, entities.PaymentCash)
+		if exited, fee, err := southExit.Release(gateTicket.ID); err == nil {
+			fmt.Printf("Entered at %s, left at %s after paying %d cents\n", exited.EntryGateID, exited.ExitGateID, fee)
+		}
+		fmt.Printf("Barrier commands: %d\n", len(barrier.Actions()))
+	}
+
+	// Example 16: Save the lot to disk and restore it
+	fmt.Println("\n=== Example 16: Snapshot and Restore ===")
+	snapshotPath := filepath.Join(os.TempDir(), "parking-lot-snapshot.json")
+	if err := parkingLot.SaveSnapshot(snapshotPath); err != nil {
+		fmt.Printf("Error saving snapshot: %v\n", err)
+	} else if snapshot, err := service.LoadSnapshot(snapshotPath); err != nil {
+		fmt.Printf("Error loading snapshot: %v\n", err)
+	} else if restored, err := service.RestoreParkingLotService(snapshot, service.WithClock(clock)); err != nil {
+		fmt.Printf("Error restoring snapshot: %v\n", err)
+	} else {
+		restoredStatus := restored.GetParkingLotStatus()
+		fmt.Printf("Restored %d floors and %d tickets from %s\n", len(restoredStatus.Floors), len(snapshot.Tickets), snapshotPath)
+		fmt.Printf("Floor 1 Car Spots before: %d occupied, after restore: %d occupied\n",
+			parkingLot.GetParkingLotStatus().Floors[0].Spots[entities.CAR].Occupied,
+			restoredStatus.Floors[0].Spots[entities.CAR].Occupied)
+	}
+
+	// Example 17: Recover from a crash with the write-ahead journal
+	fmt.Println("\n=== Example 17: Journal and Crash Recovery ===")
+	journalPath := filepath.Join(os.TempDir(), "parking-lot.journal")
+	os.Remove(journalPath)
+	journal, err := service.OpenJournal(journalPath)
+	if err != nil {
+		fmt.Printf("Error opening journal: %v\n", err)
+		return
+	}
+	journaledLot := service.NewParkingLotService(floorsConfig, pricing, service.WithClock(clock), service.WithJournal(journal))
+	journaledLot.ParkVehicle(entities.NewCar("WAL-001"))
+	journaledLot.ParkVehicle(entities.NewMotorCycle("WAL-002"))
+	journal.Close() // The process dies here
+
+	journal, err = service.OpenJournal(journalPath)
+	if err != nil {
+		fmt.Printf("Error reopening journal: %v\n", err)
+		return
+	}
+	defer journal.Close()
+	recoveredLot := service.NewParkingLotService(floorsConfig, pricing, service.WithClock(clock), service.WithJournal(journal))
+	if replayed, err := recoveredLot.Recover(); err != nil {
+		fmt.Printf("Error recovering: %v\n", err)
+	} else if recovered, err := recoveredLot.GetActiveTicketByVehicle("WAL-001"); err == nil {
+		fmt.Printf("Replayed %d records, WAL-001 is back on Floor %d, Spot %d\n", replayed, recovered.FloorID, recovered.SpotID)
+	}
+
+	// Example 18: Keep tickets in a file instead of memory
+	fmt.Println("\n=== Example 18: File-Backed Ticket Repository ===")
+	ticketsPath := filepath.Join(os.TempDir(), "parking-lot-tickets.jsonl")
+	os.Remove(ticketsPath)
+	repository, err := service.OpenFileTicketRepository(ticketsPath)
+	if err != nil {
+		fmt.Printf("Error opening ticket repository: %v\n", err)
+		return
+	}
+	defer repository.Close()
+	storedLot := service.NewParkingLotService(floorsConfig, pricing, service.WithClock(clock), service.WithTicketRepository(repository))
+	morning := clock.Now()
+	storedLot.ParkVehicle(entities.NewCar("FILE-001"))
+	clock.Advance(10 * time.Minute)
+	storedLot.ParkVehicle(entities.NewTruck("FILE-002"))
+	if tickets, err := repository.ListByTimeRange(morning, clock.Now().Add(time.Minute)); err == nil {
+		fmt.Printf("%d tickets issued since %s, stored in %s\n", len(tickets), morning.Format(time.Kitchen), ticketsPath)
+	}
+}
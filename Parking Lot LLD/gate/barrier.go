@@ -0,0 +1,78 @@
+package gate
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"parkinglot/entities"
+)
+
+var ErrBarrierFault = errors.New("barrier fault")
+
+// BarrierController drives the physical barrier arms of the lot's gates
+type BarrierController interface {
+	Open(gateID string) error
+	Close(gateID string) error
+}
+
+// BarrierAction is one open or close command sent to a barrier
+type BarrierAction struct {
+	GateID string
+	Open   bool
+	Time   time.Time
+}
+
+// SimulatedBarrier stands in for barrier hardware, keeping each gate's arm position in memory
+// Set Fault to make every command fail, e.g. to rehearse a jammed barrier
+type SimulatedBarrier struct {
+	Fault   error
+	clock   entities.Clock // stamps the actions, so they line up with the lot's tickets
+	open    map[string]bool
+	actions []BarrierAction
+	mu      sync.Mutex
+}
+
+// NewSimulatedBarrier creates a SimulatedBarrier with every arm down
+// Actions are timed by clock, usually the lot itself, or by the system clock when it is nil
+func NewSimulatedBarrier(clock entities.Clock) *SimulatedBarrier {
+	if clock == nil {
+		clock = entities.NewRealClock()
+	}
+	return &SimulatedBarrier{clock: clock, open: make(map[string]bool)}
+}
+
+func (b *SimulatedBarrier) Open(gateID string) error {
+	return b.move(gateID, true)
+}
+
+func (b *SimulatedBarrier) Close(gateID string) error {
+	return b.move(gateID, false)
+}
+
+// move raises or lowers a gate's arm and logs the command
+func (b *SimulatedBarrier) move(gateID string, open bool) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.Fault != nil {
+		return b.Fault
+	}
+	b.open[gateID] = open
+	b.actions = append(b.actions, BarrierAction{GateID: gateID, Open: open, Time: b.clock.Now()})
+	return nil
+}
+
+// IsOpen reports whether a gate's arm is up
+func (b *SimulatedBarrier) IsOpen(gateID string) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.open[gateID]
+}
+
+// Actions returns every command the barrier carried out, oldest first
+func (b *SimulatedBarrier) Actions() []BarrierAction {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return append([]BarrierAction(nil), b.actions...)
+}
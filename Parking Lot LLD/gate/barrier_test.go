@@ -0,0 +1,30 @@
+package gate
+
+import (
+	"testing"
+	"time"
+
+	"parkinglot/entities"
+	"parkinglot/service"
+)
+
+func TestSimulatedBarrierUsesLotClock(t *testing.T) {
+	clock := entities.NewManualClock(time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC))
+	lot := service.NewParkingLotService([][3]int{{2, 0, 0}}, nil, service.WithClock(clock))
+	barrier := NewSimulatedBarrier(lot)
+
+	ticket, err := NewEntryGate("N-IN", lot, barrier).Admit(entities.NewCar("GATE-1"), service.ParkOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	actions := barrier.Actions()
+	if len(actions) != 2 {
+		t.Fatalf("got %d barrier actions, want the arm raised and lowered", len(actions))
+	}
+	for _, action := range actions {
+		if !action.Time.Equal(ticket.EntryTime) {
+			t.Errorf("barrier action at %s, want the ticket's entry time %s", action.Time, ticket.EntryTime)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package gate
+
+import (
+	"fmt"
+	"sync"
+
+	"parkinglot/entities"
+	"parkinglot/service"
+)
+
+// EntryGate issues tickets to arriving vehicles and lets them in
+// A gate serves one vehicle at a time; any number of gates can work against the same lot at once
+type EntryGate struct {
+	ID      string
+	lot     *service.ParkingLotService
+	barrier BarrierController
+	mu      sync.Mutex
+}
+
+// NewEntryGate creates an entry gate for a lot
+func NewEntryGate(id string, lot *service.ParkingLotService, barrier BarrierController) *EntryGate {
+	return &EntryGate{ID: id, lot: lot, barrier: barrier}
+}
+
+// Admit issues a ticket for the vehicle and raises the barrier to let it through
+// If the barrier fails the ticket is still valid and is returned along with an ErrBarrierFault
+// error, so an attendant can let the vehicle in by hand
+func (g *EntryGate) Admit(vehicle entities.Vehicle, opts service.ParkOptions) (*entities.Ticket, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	opts.EntryGateID = g.ID
+	ticket, err := g.lot.ParkVehicleWithOptions(vehicle, opts)
+	if err != nil {
+		return nil, err
+	}
+	if err := pass(g.barrier, g.ID); err != nil {
+		return ticket, err
+	}
+	return ticket, nil
+}
+
+// ExitGate accepts tickets from leaving vehicles and lets them out once paid
+// A gate serves one vehicle at a time; any number of gates can work against the same lot at once
+type ExitGate struct {
+	ID      string
+	lot     *service.ParkingLotService
+	barrier BarrierController
+	mu      sync.Mutex
+}
+
+// NewExitGate creates an exit gate for a lot
+func NewExitGate(id string, lot *service.ParkingLotService, barrier BarrierController) *ExitGate {
+	return &ExitGate{ID: id, lot: lot, barrier: barrier}
+}
+
+// Pay takes payment for a ticket at the gate's pay station; see ParkingLotService.PayTicket
+func (g *ExitGate) Pay(ticketID string, method entities.PaymentMethod) (*entities.Payment, error) {
+	return g.lot.PayTicket(ticketID, method, 0)
+}
+
+// Release accepts a paid ticket, closes it and raises the barrier to let the vehicle out
+// The barrier stays down while the ticket is unpaid, returning service.ErrPaymentRequired
+// If the barrier fails the vehicle has already been checked out, and the ticket is returned
+// along with an ErrBarrierFault error so an attendant can let it out by hand
+func (g *ExitGate) Release(ticketID string) (*entities.Ticket, int, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	ticket, fee, err := g.lot.UnparkVehicleWithOptions(ticketID, service.UnparkOptions{ExitGateID: g.ID})
+	if err != nil {
+		return nil, 0, err
+	}
+	if err := pass(g.barrier, g.ID); err != nil {
+		return ticket, fee, err
+	}
+	return ticket, fee, nil
+}
+
+// pass raises a gate's barrier for one vehicle and lowers it behind it
+func pass(barrier BarrierController, gateID string) error {
+	if err := barrier.Open(gateID); err != nil {
+		return fmt.Errorf("%w: opening gate %s: %v", ErrBarrierFault, gateID, err)
+	}
+	if err := barrier.Close(gateID); err != nil {
+		return fmt.Errorf("%w: closing gate %s: %v", ErrBarrierFault, gateID, err)
+	}
+	return nil
+}
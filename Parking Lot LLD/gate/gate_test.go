@@ -0,0 +1,54 @@
+package gate
+
+import (
+	"errors"
+	"testing"
+
+	"parkinglot/entities"
+	"parkinglot/service"
+)
+
+func TestGatesRecordedOnTicket(t *testing.T) {
+	lot := service.NewParkingLotService([][3]int{{2, 0, 0}}, nil, service.WithGates(
+		service.Gate{ID: "N-IN", Direction: service.GateEntry},
+		service.Gate{ID: "S-OUT", Direction: service.GateExit},
+	))
+	barrier := NewSimulatedBarrier(lot)
+
+	ticket, err := NewEntryGate("N-IN", lot, barrier).Admit(entities.NewCar("GATE-1"), service.ParkOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	exit := NewExitGate("S-OUT", lot, barrier)
+	if _, err := exit.Pay(ticket.ID, entities.PaymentCash); err != nil && !errors.Is(err, service.ErrNothingToPay) {
+		t.Fatal(err)
+	}
+	exited, _, err := exit.Release(ticket.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exited.EntryGateID != "N-IN" || exited.ExitGateID != "S-OUT" {
+		t.Errorf("ticket entered at %q and left at %q, want N-IN and S-OUT", exited.EntryGateID, exited.ExitGateID)
+	}
+}
+
+func TestUndeclaredGateKeepsBarrierDown(t *testing.T) {
+	lot := service.NewParkingLotService([][3]int{{2, 0, 0}}, nil, service.WithGates(
+		service.Gate{ID: "N-IN", Direction: service.GateEntry},
+		service.Gate{ID: "S-OUT", Direction: service.GateExit},
+	))
+	barrier := NewSimulatedBarrier(lot)
+
+	for _, id := range []string{"W-IN", "S-OUT"} {
+		_, err := NewEntryGate(id, lot, barrier).Admit(entities.NewCar("GATE-1"), service.ParkOptions{})
+		if !errors.Is(err, service.ErrUnknownGate) {
+			t.Errorf("admitting through %s: got %v, want ErrUnknownGate", id, err)
+		}
+	}
+	if actions := barrier.Actions(); len(actions) != 0 {
+		t.Errorf("barrier moved %d times for rejected gates", len(actions))
+	}
+	if status := lot.GetParkingLotStatus(); status.TotalActiveTickets != 0 {
+		t.Errorf("%d tickets issued through rejected gates", status.TotalActiveTickets)
+	}
+}
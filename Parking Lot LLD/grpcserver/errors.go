@@ -0,0 +1,56 @@
+package grpcserver
+
+import (
+	"errors"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"parkinglot/entities"
+	"parkinglot/service"
+)
+
+var (
+	// errInvalidArgument marks request fields the server could not accept
+	errInvalidArgument = errors.New("invalid argument")
+	// errShuttingDown ends availability streams when the server is closed
+	errShuttingDown = errors.New("server is shutting down")
+)
+
+// errorCode ties a service error to the gRPC code it is reported with
+type errorCode struct {
+	err  error
+	code codes.Code
+}
+
+// errorCodes is checked in order with errors.Is, so wrapped errors map like their causes
+var errorCodes = []errorCode{
+	{errInvalidArgument, codes.InvalidArgument},
+	{service.ErrInvalidVehicle, codes.InvalidArgument},
+	{entities.ErrInvalidVehicleType, codes.InvalidArgument},
+	{service.ErrInvalidFloor, codes.InvalidArgument},
+	{service.ErrNoPaymentProcessor, codes.InvalidArgument},
+	{service.ErrUnknownGate, codes.InvalidArgument},
+	{service.ErrTicketNotFound, codes.NotFound},
+	{service.ErrVehicleNotParked, codes.NotFound},
+	{service.ErrVehicleAlreadyParked, codes.AlreadyExists},
+	{service.ErrParkingLotFull, codes.ResourceExhausted},
+	{service.ErrTicketVoided, codes.FailedPrecondition},
+	{service.ErrNothingToPay, codes.FailedPrecondition},
+	{service.ErrPaymentRequired, codes.FailedPrecondition},
+	{service.ErrPaymentFailed, codes.Aborted},
+	{errShuttingDown, codes.Unavailable},
+}
+
+// statusError converts err to a gRPC status error with the code mapped from it
+// Errors without a mapping are internal; their details are logged rather than sent to the client
+func (s *Server) statusError(method string, err error) error {
+	for _, mapping := range errorCodes {
+		if errors.Is(err, mapping.err) {
+			return status.Error(mapping.code, err.Error())
+		}
+	}
+
+	s.logger.Printf("%s: %v", method, err)
+	return status.Error(codes.Internal, "internal server error")
+}
@@ -0,0 +1,292 @@
+// Package grpcserver exposes a parking lot over gRPC for kiosks and gate controllers
+// The service is defined in proto/parking.proto
+package grpcserver
+
+//go:generate protoc -I ../proto --go_out=../proto/parkingpb --go_opt=paths=source_relative --go-grpc_out=../proto/parkingpb --go-grpc_opt=paths=source_relative parking.proto
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"parkinglot/entities"
+	"parkinglot/proto/parkingpb"
+	"parkinglot/service"
+)
+
+// watchBufferSize bounds the availability changes queued for a stream that is slow to send them
+const watchBufferSize = 64
+
+// Server implements parkingpb.ParkingLotServer on top of a lot
+type Server struct {
+	parkingpb.UnimplementedParkingLotServer
+
+	lot       *service.ParkingLotService
+	logger    *log.Logger
+	closing   chan struct{} // closed by Close to end availability streams
+	closeOnce sync.Once
+}
+
+// NewServer creates a gRPC server for a lot
+// Unexpected errors are logged to logger, or to the standard logger when it is nil
+func NewServer(lot *service.ParkingLotService, logger *log.Logger) *Server {
+	if logger == nil {
+		logger = log.Default()
+	}
+	return &Server{lot: lot, logger: logger, closing: make(chan struct{})}
+}
+
+// Close ends every availability stream with codes.Unavailable and refuses new ones
+// Streams never finish on their own, so call it before GracefulStop, which then only waits for unary calls
+func (s *Server) Close() {
+	s.closeOnce.Do(func() { close(s.closing) })
+}
+
+// Register registers the server's service on a gRPC server
+func (s *Server) Register(registrar grpc.ServiceRegistrar) {
+	parkingpb.RegisterParkingLotServer(registrar, s)
+}
+
+func (s *Server) Park(ctx context.Context, req *parkingpb.ParkRequest) (*parkingpb.ParkResponse, error) {
+	vehicle, opts, err := parkRequest(req)
+	if err != nil {
+		return nil, s.statusError("Park", err)
+	}
+	ticket, err := s.lot.ParkVehicleWithOptions(vehicle, opts)
+	if err != nil {
+		return nil, s.statusError("Park", err)
+	}
+	return &parkingpb.ParkResponse{Ticket: s.ticket(ticket)}, nil
+}
+
+// parkRequest builds the vehicle and park options a request describes
+func parkRequest(req *parkingpb.ParkRequest) (entities.Vehicle, service.ParkOptions, error) {
+	plate := strings.TrimSpace(req.GetNumberPlate())
+	if plate == "" {
+		return nil, service.ParkOptions{}, fmt.Errorf("%w: number_plate is required", service.ErrInvalidVehicle)
+	}
+	vehicleType, ok := entities.VehicleTypeByName(req.GetVehicleType())
+	if !ok {
+		return nil, service.ParkOptions{}, fmt.Errorf("%w: %q", entities.ErrInvalidVehicleType, req.GetVehicleType())
+	}
+	spots := int(req.GetSpots())
+	if spots == 0 {
+		spots = 1
+	}
+	if spots < 0 {
+		return nil, service.ParkOptions{}, fmt.Errorf("%w: spots must be positive", errInvalidArgument)
+	}
+	vehicle, err := entities.NewVehicleOfType(vehicleType, plate, spots)
+	if err != nil {
+		return nil, service.ParkOptions{}, err
+	}
+
+	opts := service.ParkOptions{EntryGateID: req.GetEntryGateId()}
+	if opts.Requirements.Require, err = entities.ParseSpotAttributes(req.GetRequire()...); err != nil {
+		return nil, service.ParkOptions{}, fmt.Errorf("%w: %v", errInvalidArgument, err)
+	}
+	if opts.Requirements.Prefer, err = entities.ParseSpotAttributes(req.GetPrefer()...); err != nil {
+		return nil, service.ParkOptions{}, fmt.Errorf("%w: %v", errInvalidArgument, err)
+	}
+	opts.Requirements.MinChargerKW = req.GetMinChargerKw()
+	return vehicle, opts, nil
+}
+
+func (s *Server) Pay(ctx context.Context, req *parkingpb.PayRequest) (*parkingpb.PayResponse, error) {
+	if req.GetTicketId() == "" {
+		return nil, s.statusError("Pay", fmt.Errorf("%w: ticket_id is required", errInvalidArgument))
+	}
+	if req.GetAmount() < 0 {
+		return nil, s.statusError("Pay", fmt.Errorf("%w: amount must not be negative", errInvalidArgument))
+	}
+
+	method := entities.PaymentMethod(strings.ToLower(strings.TrimSpace(req.GetMethod())))
+	payment, err := s.lot.PayTicket(req.GetTicketId(), method, int(req.GetAmount()))
+	if err != nil {
+		return nil, s.statusError("Pay", err)
+	}
+	return &parkingpb.PayResponse{Payment: paymentMessage(payment)}, nil
+}
+
+func (s *Server) Unpark(ctx context.Context, req *parkingpb.UnparkRequest) (*parkingpb.UnparkResponse, error) {
+	if req.GetTicketId() == "" {
+		return nil, s.statusError("Unpark", fmt.Errorf("%w: ticket_id is required", errInvalidArgument))
+	}
+
+	ticket, _, err := s.lot.UnparkVehicleWithOptions(req.GetTicketId(), service.UnparkOptions{ExitGateID: req.GetExitGateId()})
+	if err != nil {
+		return nil, s.statusError("Unpark", err)
+	}
+	return &parkingpb.UnparkResponse{Ticket: s.ticket(ticket)}, nil
+}
+
+func (s *Server) GetStatus(ctx context.Context, req *parkingpb.GetStatusRequest) (*parkingpb.GetStatusResponse, error) {
+	status := s.lot.GetParkingLotStatus()
+	resp := &parkingpb.GetStatusResponse{
+		Floors:             make([]*parkingpb.FloorStatus, len(status.Floors)),
+		TotalActiveTickets: int32(status.TotalActiveTickets),
+	}
+	for i, floor := range status.Floors {
+		floorResp := &parkingpb.FloorStatus{FloorId: int32(floor.FloorID), Name: floor.Name, Closed: floor.Closed}
+		for _, spotType := range floor.SpotKinds() {
+			spots := floor.Spots[spotType]
+			floorResp.Spots = append(floorResp.Spots, &parkingpb.SpotsSummary{
+				SpotType:     spotType.String(),
+				Total:        int32(spots.Total),
+				Occupied:     int32(spots.Occupied),
+				Vacant:       int32(spots.Vacant),
+				Reserved:     int32(spots.Reserved),
+				OutOfService: int32(spots.OutOfService),
+				Blocked:      int32(spots.Blocked),
+			})
+		}
+		resp.Floors[i] = floorResp
+	}
+	return resp, nil
+}
+
+// WatchAvailability sends the current availability of the watched floors, then every change to it
+// Changes are queued without ever holding up the lot; a stream that falls too far behind to queue
+// them is sent the current availability again instead
+// The stream ends when the client cancels it or the server is closed
+func (s *Server) WatchAvailability(req *parkingpb.WatchAvailabilityRequest, stream grpc.ServerStreamingServer[parkingpb.AvailabilityUpdate]) error {
+	select {
+	case <-s.closing:
+		return s.statusError("WatchAvailability", errShuttingDown)
+	default:
+	}
+
+	var watched map[int]bool
+	if len(req.GetFloorIds()) > 0 {
+		watched = make(map[int]bool, len(req.GetFloorIds()))
+		for _, floorID := range req.GetFloorIds() {
+			watched[int(floorID)] = true
+		}
+	}
+	watches := func(floorID int) bool {
+		return watched == nil || watched[floorID]
+	}
+
+	updates := make(chan service.AvailabilityChanged, watchBufferSize)
+	behind := make(chan struct{}, 1)
+	subscription := s.lot.Subscribe(func(event service.Event) {
+		changed := event.(service.AvailabilityChanged)
+		if !watches(changed.FloorID) {
+			return
+		}
+		select {
+		case updates <- changed:
+		default:
+			select {
+			case behind <- struct{}{}:
+			default:
+			}
+		}
+	}, service.DeliverSync, service.EventAvailabilityChanged)
+	defer s.lot.Unsubscribe(subscription)
+
+	// Subscribed first, so no change between reading the availability and watching it is missed
+	if err := s.sendAvailability(stream, watches); err != nil {
+		return err
+	}
+	for {
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case <-s.closing:
+			return s.statusError("WatchAvailability", errShuttingDown)
+		case changed := <-updates:
+			if err := stream.Send(availabilityUpdate(changed)); err != nil {
+				return err
+			}
+		case <-behind:
+			for len(updates) > 0 {
+				<-updates
+			}
+			if err := s.sendAvailability(stream, watches); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// sendAvailability sends the current availability of every spot kind on the watched floors
+func (s *Server) sendAvailability(stream grpc.ServerStreamingServer[parkingpb.AvailabilityUpdate], watches func(floorID int) bool) error {
+	now := s.lot.Now()
+	for _, floor := range s.lot.GetParkingLotStatus().Floors {
+		if !watches(floor.FloorID) {
+			continue
+		}
+		for _, spotType := range floor.SpotKinds() {
+			spots := floor.Spots[spotType]
+			update := availabilityUpdate(service.AvailabilityChanged{At: now, FloorID: floor.FloorID, SpotType: spotType, Vacant: spots.Vacant, Total: spots.Total})
+			if err := stream.Send(update); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func availabilityUpdate(changed service.AvailabilityChanged) *parkingpb.AvailabilityUpdate {
+	return &parkingpb.AvailabilityUpdate{
+		Time:     timestamppb.New(changed.At),
+		FloorId:  int32(changed.FloorID),
+		SpotType: changed.SpotType.String(),
+		Vacant:   int32(changed.Vacant),
+		Total:    int32(changed.Total),
+	}
+}
+
+// ticket describes a ticket, including what is due if the vehicle left now
+func (s *Server) ticket(ticket *entities.Ticket) *parkingpb.Ticket {
+	msg := &parkingpb.Ticket{
+		Id:           ticket.ID,
+		Status:       parkingpb.TicketStatus_TICKET_STATUS_ACTIVE,
+		NumberPlate:  ticket.Vehicle.GetNumberPlate(),
+		VehicleType:  ticket.VehicleType.String(),
+		FloorId:      int32(ticket.FloorID),
+		SpotType:     ticket.SpotType.String(),
+		SpotIds:      make([]int32, len(ticket.SpotIDs)),
+		EntryTime:    timestamppb.New(ticket.EntryTime),
+		PricePerHour: int64(ticket.PricePerHour),
+		PaidAmount:   int64(ticket.PaidAmount),
+		Fee:          int64(ticket.Fee),
+		ReplacedById: ticket.ReplacedByID,
+		EntryGateId:  ticket.EntryGateID,
+		ExitGateId:   ticket.ExitGateID,
+	}
+	for i, spotID := range ticket.SpotIDs {
+		msg.SpotIds[i] = int32(spotID)
+	}
+	switch {
+	case ticket.IsVoided():
+		msg.Status = parkingpb.TicketStatus_TICKET_STATUS_VOIDED
+	case !ticket.IsActive():
+		msg.Status = parkingpb.TicketStatus_TICKET_STATUS_EXITED
+		msg.ExitTime = timestamppb.New(ticket.ExitTime)
+	default:
+		if due, err := s.lot.AmountDue(ticket.ID); err == nil {
+			msg.AmountDue = int64(due)
+		}
+	}
+	return msg
+}
+
+func paymentMessage(payment *entities.Payment) *parkingpb.Payment {
+	return &parkingpb.Payment{
+		Id:            payment.ID,
+		TicketId:      payment.TicketID,
+		Amount:        int64(payment.Amount),
+		Method:        string(payment.Method),
+		Status:        string(payment.Status),
+		Reference:     payment.Reference,
+		FailureReason: payment.FailureReason,
+		CreatedAt:     timestamppb.New(payment.CreatedAt),
+	}
+}
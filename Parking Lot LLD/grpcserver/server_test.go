@@ -0,0 +1,67 @@
+package grpcserver
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	"parkinglot/proto/parkingpb"
+	"parkinglot/service"
+)
+
+func TestCloseEndsAvailabilityStreams(t *testing.T) {
+	lot := service.NewParkingLotService([][3]int{{2, 1, 1}}, nil)
+	listener := bufconn.Listen(1 << 20)
+	grpcServer := grpc.NewServer()
+	server := NewServer(lot, nil)
+	server.Register(grpcServer)
+	go grpcServer.Serve(listener)
+	defer grpcServer.Stop()
+
+	conn, err := grpc.NewClient("passthrough:///bufconn",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	stream, err := parkingpb.NewParkingLotClient(conn).WatchAvailability(ctx, &parkingpb.WatchAvailabilityRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := stream.Recv(); err != nil {
+		t.Fatal(err)
+	}
+
+	// With the stream ended by Close, a graceful stop has nothing left to wait for
+	server.Close()
+	stopped := make(chan struct{})
+	go func() {
+		grpcServer.GracefulStop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-time.After(2 * time.Second):
+		t.Fatal("GracefulStop still waiting for the availability stream")
+	}
+
+	for {
+		if _, err := stream.Recv(); err != nil {
+			if code := status.Code(err); code != codes.Unavailable {
+				t.Errorf("stream ended with %v, want Unavailable", err)
+			}
+			break
+		}
+	}
+}
@@ -0,0 +1,36 @@
+// Package lotconfig reads lot configuration files written in YAML or JSON
+// It keeps the YAML dependency out of the service package, which only parses JSON
+package lotconfig
+
+import (
+	"fmt"
+	"os"
+
+	"sigs.k8s.io/yaml"
+
+	"parkinglot/service"
+)
+
+// Load reads a lot configuration from a YAML or JSON file
+func Load(path string) (*service.LotConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	config, err := Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return config, nil
+}
+
+// Parse parses a lot configuration written in YAML or JSON
+// Unknown fields are rejected like service.ParseLotConfig does; the configuration is not validated
+func Parse(data []byte) (*service.LotConfig, error) {
+	// JSON is valid YAML, so both go through the same conversion
+	data, err := yaml.YAMLToJSON(data)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", service.ErrInvalidConfig, err)
+	}
+	return service.ParseLotConfig(data)
+}
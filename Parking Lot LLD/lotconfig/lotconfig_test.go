@@ -0,0 +1,57 @@
+package lotconfig
+
+import (
+	"errors"
+	"testing"
+
+	"parkinglot/service"
+)
+
+func TestLoadExample(t *testing.T) {
+	config, err := Load("../lot.example.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	pls, err := service.NewParkingLotServiceFromConfig(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	status := pls.GetParkingLotStatus()
+	if len(status.Floors) != 2 || status.Floors[0].Name != "Ground" || status.Floors[1].Name != "Level 1" {
+		t.Errorf("floors = %+v, want Ground and Level 1", status.Floors)
+	}
+	if gates := pls.Gates(); len(gates) != 2 {
+		t.Errorf("got %d gates, want 2", len(gates))
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		wantErr bool
+	}{
+		{"yaml", "floors:\n  - spots:\n      - {kind: car, capacity: 3}\n", false},
+		{"json", `{"floors": [{"spots": [{"kind": "car", "capacity": 3}]}]}`, false},
+		{"unknown field", "floors:\n  - spots:\n      - {kind: car, capacity: 3, colour: red}\n", true},
+		{"malformed yaml", "floors: [\n", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config, err := Parse([]byte(tt.data))
+			if tt.wantErr {
+				if !errors.Is(err, service.ErrInvalidConfig) {
+					t.Errorf("got %v, want ErrInvalidConfig", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(config.Floors) != 1 || config.Floors[0].Spots[0].Capacity != 3 {
+				t.Errorf("parsed %+v, want one floor with 3 car spots", config)
+			}
+		})
+	}
+}
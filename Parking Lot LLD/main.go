@@ -1 +1,314 @@
-package main
\ No newline at end of file
+// Command parkinglot is an operator's tool for a parking lot
+//
+//	parkinglot [flags] <command> [args]
+//
+// Commands:
+//
+//	park <type> <plate>       park a vehicle and print its ticket
+//	unpark <ticket> [method]  release a vehicle, first paying what is due by method if one is given
+//	find <plate>              print a parked vehicle's active ticket
+//	status                    print the occupancy of every floor
+//	close-floor <floor>       take a floor out of service
+//	shell                     run commands read from standard input, one per line
+//	example                   run the scripted walkthrough of the lot's features
+//
+// With -state the lot is loaded from a snapshot file, or built from -config or -floors when the file
+// does not exist yet, and saved back after every command that changes it. Without -state the lot lives only
+// as long as the process, which suits shell
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"parkinglot/entities"
+	"parkinglot/lotconfig"
+	"parkinglot/service"
+)
+
+// errUsage marks commands given the wrong arguments
+var errUsage = errors.New("usage")
+
+// command is a subcommand the tool offers
+type command struct {
+	name    string
+	args    string // Argument synopsis for usage messages
+	minArgs int
+	maxArgs int
+	mutates bool // Whether the lot must be saved to -state afterwards
+	run     func(c *cli, args []string) error
+}
+
+// commands are the subcommands available both on the command line and in the shell
+var commands = []command{
+	{"park", "<type> <plate>", 2, 2, true, (*cli).park},
+	{"unpark", "<ticket> [method]", 1, 2, true, (*cli).unpark},
+	{"find", "<plate>", 1, 1, false, (*cli).find},
+	{"status", "", 0, 0, false, (*cli).status},
+	{"close-floor", "<floor>", 1, 1, true, (*cli).closeFloor},
+}
+
+// synopsis returns the command with its arguments, e.g. "find <plate>"
+func (cmd command) synopsis() string {
+	return strings.TrimSpace(cmd.name + " " + cmd.args)
+}
+
+func findCommand(name string) (command, bool) {
+	for _, cmd := range commands {
+		if cmd.name == name {
+			return cmd, true
+		}
+	}
+	return command{}, false
+}
+
+// cli runs commands against one lot
+type cli struct {
+	lot       *service.ParkingLotService
+	statePath string // Snapshot file the lot is saved to; empty keeps it in memory
+	operator  string
+	format    outputFormat
+	stdout    io.Writer
+	stderr    io.Writer
+}
+
+func main() {
+	flags := flag.NewFlagSet("parkinglot", flag.ExitOnError)
+	statePath := flags.String("state", "", "snapshot file to load the lot from and save it to")
+	floors := flags.String("floors", "10/20/5,15/25/3,20/30/2", "floors of a new lot, each cars/motorcycles/trucks")
+	configPath := flags.String("config", "", "YAML or JSON configuration of a new lot; overrides -floors")
+	format := flags.String("format", "table", "output format: table or json")
+	operator := flags.String("operator", "cli", "operator recorded in the audit log for close-floor")
+	flags.Usage = func() { printUsage(flags) }
+	flags.Parse(os.Args[1:])
+
+	args := flags.Args()
+	if len(args) == 0 {
+		flags.Usage()
+		os.Exit(2)
+	}
+	if args[0] == "example" {
+		runExample()
+		return
+	}
+
+	outFormat, err := parseOutputFormat(*format)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "parkinglot: %v\n", err)
+		os.Exit(2)
+	}
+	lot, err := openLot(*statePath, *configPath, *floors)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "parkinglot: %v\n", err)
+		os.Exit(1)
+	}
+
+	c := &cli{lot: lot, statePath: *statePath, operator: *operator, format: outFormat, stdout: os.Stdout, stderr: os.Stderr}
+	if args[0] == "shell" {
+		if len(args) > 1 {
+			flags.Usage()
+			os.Exit(2)
+		}
+		c.shell(os.Stdin)
+		return
+	}
+	if err := c.execute(args); err != nil {
+		fmt.Fprintf(os.Stderr, "parkinglot: %v\n", err)
+		if errors.Is(err, errUsage) {
+			os.Exit(2)
+		}
+		os.Exit(1)
+	}
+}
+
+func printUsage(flags *flag.FlagSet) {
+	out := flags.Output()
+	fmt.Fprintln(out, "Usage: parkinglot [flags] <command> [args]")
+	fmt.Fprintln(out, "\nCommands:")
+	for _, cmd := range commands {
+		fmt.Fprintf(out, "  %s\n", cmd.synopsis())
+	}
+	fmt.Fprintln(out, "  shell")
+	fmt.Fprintln(out, "  example")
+	fmt.Fprintln(out, "\nFlags:")
+	flags.PrintDefaults()
+}
+
+// openLot loads the lot saved in statePath, or builds a new one from a configuration file or floors
+// spec when there is no state file or it does not exist yet
+func openLot(statePath, configPath, floors string) (*service.ParkingLotService, error) {
+	var config *service.LotConfig
+	if configPath != "" {
+		var err error
+		if config, err = lotconfig.Load(configPath); err != nil {
+			return nil, err
+		}
+	}
+
+	if statePath != "" {
+		snapshot, err := service.LoadSnapshot(statePath)
+		if err == nil {
+			// The snapshot holds the floors and rates; the configuration still supplies the pricing
+			// policy the snapshot's tickets were issued under, and the gates
+			var opts []service.Option
+			if config != nil {
+				if opts, err = config.Options(); err != nil {
+					return nil, err
+				}
+			}
+			return service.RestoreParkingLotService(snapshot, opts...)
+		}
+		if !errors.Is(err, os.ErrNotExist) {
+			return nil, err
+		}
+	}
+
+	if config != nil {
+		return service.NewParkingLotServiceFromConfig(config)
+	}
+
+	floorsConfig, err := parseFloors(floors)
+	if err != nil {
+		return nil, fmt.Errorf("invalid -floors: %w", err)
+	}
+	return service.NewParkingLotService(floorsConfig, nil), nil
+}
+
+// parseFloors parses "10/20/5,15/25/3" into per-floor [cars, motorcycles, trucks] capacities
+func parseFloors(spec string) ([][3]int, error) {
+	var floors [][3]int
+	for i, floor := range strings.Split(spec, ",") {
+		parts := strings.Split(strings.TrimSpace(floor), "/")
+		if len(parts) != 3 {
+			return nil, fmt.Errorf("floor %d: want cars/motorcycles/trucks, got %q", i+1, floor)
+		}
+		var capacities [3]int
+		for j, part := range parts {
+			n, err := strconv.Atoi(strings.TrimSpace(part))
+			if err != nil || n < 0 {
+				return nil, fmt.Errorf("floor %d: invalid capacity %q", i+1, part)
+			}
+			capacities[j] = n
+		}
+		floors = append(floors, capacities)
+	}
+	return floors, nil
+}
+
+// execute runs one command line, saving the lot afterwards if the command changed it
+func (c *cli) execute(args []string) error {
+	cmd, ok := findCommand(args[0])
+	if !ok {
+		return fmt.Errorf("%w: unknown command %q", errUsage, args[0])
+	}
+	args = args[1:]
+	if len(args) < cmd.minArgs || len(args) > cmd.maxArgs {
+		return fmt.Errorf("%w: %s", errUsage, cmd.synopsis())
+	}
+
+	if err := cmd.run(c, args); err != nil {
+		return err
+	}
+	if cmd.mutates && c.statePath != "" {
+		if err := c.lot.SaveSnapshot(c.statePath); err != nil {
+			return fmt.Errorf("save state: %w", err)
+		}
+	}
+	return nil
+}
+
+// shell runs commands read from in until it ends or the operator quits
+// A failing command is reported and the shell carries on
+func (c *cli) shell(in io.Reader) {
+	scanner := bufio.NewScanner(in)
+	for {
+		fmt.Fprint(c.stderr, "parking> ")
+		if !scanner.Scan() {
+			fmt.Fprintln(c.stderr)
+			return
+		}
+
+		args := strings.Fields(scanner.Text())
+		if len(args) == 0 {
+			continue
+		}
+		switch args[0] {
+		case "quit", "exit":
+			return
+		case "help":
+			for _, cmd := range commands {
+				fmt.Fprintf(c.stderr, "  %s\n", cmd.synopsis())
+			}
+			fmt.Fprintln(c.stderr, "  help")
+			fmt.Fprintln(c.stderr, "  quit")
+			continue
+		}
+		if err := c.execute(args); err != nil {
+			fmt.Fprintf(c.stderr, "error: %v\n", err)
+		}
+	}
+}
+
+func (c *cli) park(args []string) error {
+	vehicleType, ok := entities.VehicleTypeByName(args[0])
+	if !ok {
+		return fmt.Errorf("%w: %q", entities.ErrInvalidVehicleType, args[0])
+	}
+	vehicle, err := entities.NewVehicleOfType(vehicleType, args[1], 1)
+	if err != nil {
+		return err
+	}
+
+	ticket, err := c.lot.ParkVehicle(vehicle)
+	if err != nil {
+		return err
+	}
+	return c.print(c.ticketView(ticket))
+}
+
+func (c *cli) unpark(args []string) error {
+	ticketID := args[0]
+	if len(args) == 2 {
+		method := entities.PaymentMethod(strings.ToLower(args[1]))
+		if _, err := c.lot.PayTicket(ticketID, method, 0); err != nil && !errors.Is(err, service.ErrNothingToPay) {
+			return err
+		}
+	}
+
+	ticket, _, err := c.lot.UnparkVehicle(ticketID)
+	if err != nil {
+		return err
+	}
+	return c.print(c.ticketView(ticket))
+}
+
+func (c *cli) find(args []string) error {
+	ticket, err := c.lot.GetActiveTicketByVehicle(args[0])
+	if err != nil {
+		return err
+	}
+	return c.print(c.ticketView(ticket))
+}
+
+func (c *cli) status(args []string) error {
+	return c.print(statusViewOf(c.lot.GetParkingLotStatus()))
+}
+
+func (c *cli) closeFloor(args []string) error {
+	floorID, err := strconv.Atoi(args[0])
+	if err != nil {
+		return fmt.Errorf("%w: floor must be a number, got %q", errUsage, args[0])
+	}
+	if err := c.lot.CloseFloor(floorID, c.operator); err != nil {
+		return err
+	}
+
+	status := statusViewOf(c.lot.GetParkingLotStatus())
+	return c.print(status.Floors[floorID-1])
+}
@@ -0,0 +1,201 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"parkinglot/entities"
+	"parkinglot/service"
+)
+
+// outputFormat is how command results are printed
+type outputFormat string
+
+const (
+	formatTable outputFormat = "table"
+	formatJSON  outputFormat = "json"
+)
+
+func parseOutputFormat(name string) (outputFormat, error) {
+	switch format := outputFormat(strings.ToLower(name)); format {
+	case formatTable, formatJSON:
+		return format, nil
+	default:
+		return "", fmt.Errorf("unknown format %q, want table or json", name)
+	}
+}
+
+// tableView is a command result that can print itself as an aligned table
+type tableView interface {
+	writeTable(w io.Writer)
+}
+
+// print writes a command result in the chosen format
+func (c *cli) print(view tableView) error {
+	if c.format == formatJSON {
+		encoder := json.NewEncoder(c.stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(view)
+	}
+
+	w := tabwriter.NewWriter(c.stdout, 0, 0, 2, ' ', 0)
+	view.writeTable(w)
+	return w.Flush()
+}
+
+// ticketView describes a ticket
+type ticketView struct {
+	ID           string     `json:"id"`
+	Status       string     `json:"status"` // "active", "exited" or "voided"
+	NumberPlate  string     `json:"number_plate"`
+	VehicleType  string     `json:"vehicle_type"`
+	FloorID      int        `json:"floor_id"`
+	SpotType     string     `json:"spot_type"`
+	SpotIDs      []int      `json:"spot_ids"`
+	EntryTime    time.Time  `json:"entry_time"`
+	ExitTime     *time.Time `json:"exit_time,omitempty"`
+	PricePerHour int        `json:"price_per_hour"`
+	PaidAmount   int        `json:"paid_amount"`
+	AmountDue    int        `json:"amount_due"`
+	Fee          int        `json:"fee,omitempty"`
+}
+
+// ticketView describes a ticket, including what is due if the vehicle left now
+func (c *cli) ticketView(ticket *entities.Ticket) ticketView {
+	view := ticketView{
+		ID:           ticket.ID,
+		Status:       "active",
+		NumberPlate:  ticket.Vehicle.GetNumberPlate(),
+		VehicleType:  ticket.VehicleType.String(),
+		FloorID:      ticket.FloorID,
+		SpotType:     ticket.SpotType.String(),
+		SpotIDs:      ticket.SpotIDs,
+		EntryTime:    ticket.EntryTime,
+		PricePerHour: ticket.PricePerHour,
+		PaidAmount:   ticket.PaidAmount,
+		Fee:          ticket.Fee,
+	}
+	switch {
+	case ticket.IsVoided():
+		view.Status = "voided"
+	case !ticket.IsActive():
+		view.Status = "exited"
+		exitTime := ticket.ExitTime
+		view.ExitTime = &exitTime
+	default:
+		if due, err := c.lot.AmountDue(ticket.ID); err == nil {
+			view.AmountDue = due
+		}
+	}
+	return view
+}
+
+func (v ticketView) writeTable(w io.Writer) {
+	spotIDs := make([]string, len(v.SpotIDs))
+	for i, id := range v.SpotIDs {
+		spotIDs[i] = fmt.Sprint(id)
+	}
+
+	fmt.Fprintf(w, "Ticket\t%s\n", v.ID)
+	fmt.Fprintf(w, "Status\t%s\n", v.Status)
+	fmt.Fprintf(w, "Vehicle\t%s %s\n", v.VehicleType, v.NumberPlate)
+	fmt.Fprintf(w, "Spot\tfloor %d, %s %s\n", v.FloorID, v.SpotType, strings.Join(spotIDs, ","))
+	fmt.Fprintf(w, "Entered\t%s\n", v.EntryTime.Format(time.RFC3339))
+	if v.ExitTime != nil {
+		fmt.Fprintf(w, "Exited\t%s\n", v.ExitTime.Format(time.RFC3339))
+	}
+	fmt.Fprintf(w, "Rate\t%s/hour\n", formatCents(v.PricePerHour))
+	fmt.Fprintf(w, "Paid\t%s\n", formatCents(v.PaidAmount))
+	if v.Status == "active" {
+		fmt.Fprintf(w, "Due\t%s\n", formatCents(v.AmountDue))
+	} else if v.Status == "exited" {
+		fmt.Fprintf(w, "Fee\t%s\n", formatCents(v.Fee))
+	}
+}
+
+// statusView describes the occupancy of the whole lot
+type statusView struct {
+	Floors             []floorView `json:"floors"`
+	TotalActiveTickets int         `json:"total_active_tickets"`
+}
+
+// floorView describes the occupancy of one floor by spot kind
+type floorView struct {
+	FloorID int         `json:"floor_id"`
+	Name    string      `json:"name,omitempty"`
+	Closed  bool        `json:"closed"`
+	Spots   []spotsView `json:"spots"`
+}
+
+// spotsView counts the spots of one kind by state
+type spotsView struct {
+	SpotType     string `json:"spot_type"`
+	Total        int    `json:"total"`
+	Occupied     int    `json:"occupied"`
+	Vacant       int    `json:"vacant"`
+	Reserved     int    `json:"reserved"`
+	OutOfService int    `json:"out_of_service"`
+	Blocked      int    `json:"blocked"`
+}
+
+// statusViewOf describes a lot's status, listing spot kinds in registry order
+func statusViewOf(status *service.ParkingLotStatus) statusView {
+	view := statusView{Floors: make([]floorView, len(status.Floors)), TotalActiveTickets: status.TotalActiveTickets}
+	for i, floor := range status.Floors {
+		floorView := floorView{FloorID: floor.FloorID, Name: floor.Name, Closed: floor.Closed}
+		for _, spotType := range floor.SpotKinds() {
+			spots := floor.Spots[spotType]
+			floorView.Spots = append(floorView.Spots, spotsView{
+				SpotType:     spotType.String(),
+				Total:        spots.Total,
+				Occupied:     spots.Occupied,
+				Vacant:       spots.Vacant,
+				Reserved:     spots.Reserved,
+				OutOfService: spots.OutOfService,
+				Blocked:      spots.Blocked,
+			})
+		}
+		view.Floors[i] = floorView
+	}
+	return view
+}
+
+func (v statusView) writeTable(w io.Writer) {
+	writeFloorHeader(w)
+	for _, floor := range v.Floors {
+		floor.writeRows(w)
+	}
+	fmt.Fprintf(w, "\nActive tickets: %d\n", v.TotalActiveTickets)
+}
+
+func (v floorView) writeTable(w io.Writer) {
+	writeFloorHeader(w)
+	v.writeRows(w)
+}
+
+func writeFloorHeader(w io.Writer) {
+	fmt.Fprintln(w, "FLOOR\tKIND\tTOTAL\tOCCUPIED\tVACANT\tRESERVED\tOUT OF SERVICE\tBLOCKED")
+}
+
+func (v floorView) writeRows(w io.Writer) {
+	floor := fmt.Sprint(v.FloorID)
+	if v.Name != "" {
+		floor += " " + v.Name
+	}
+	if v.Closed {
+		floor += " (closed)"
+	}
+	for _, spots := range v.Spots {
+		fmt.Fprintf(w, "%s\t%s\t%d\t%d\t%d\t%d\t%d\t%d\n", floor, spots.SpotType, spots.Total, spots.Occupied,
+			spots.Vacant, spots.Reserved, spots.OutOfService, spots.Blocked)
+	}
+}
+
+// formatCents formats an amount in cents as dollars, e.g. 250 as "$2.50"
+func formatCents(cents int) string {
+	return fmt.Sprintf("$%d.%02d", cents/100, cents%100)
+}
@@ -0,0 +1,1250 @@
+// Parking operations for kiosks and gate controllers
+//
+// Errors are reported with gRPC status codes derived from the lot's errors:
+//   INVALID_ARGUMENT     malformed request, unknown vehicle type, floor, gate or payment method
+//   NOT_FOUND            unknown ticket, or a vehicle that is not parked
+//   ALREADY_EXISTS       the vehicle is already parked
+//   RESOURCE_EXHAUSTED   no spot fits the vehicle
+//   FAILED_PRECONDITION  the ticket is voided, has nothing to pay or must be paid before exit
+//   ABORTED              the payment was declined
+//   INTERNAL             anything else
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.9
+// 	protoc        (unknown)
+// source: parking.proto
+
+package parkingpb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type TicketStatus int32
+
+const (
+	TicketStatus_TICKET_STATUS_UNSPECIFIED TicketStatus = 0
+	TicketStatus_TICKET_STATUS_ACTIVE      TicketStatus = 1
+	TicketStatus_TICKET_STATUS_EXITED      TicketStatus = 2
+	TicketStatus_TICKET_STATUS_VOIDED      TicketStatus = 3
+)
+
+// Enum value maps for TicketStatus.
+var (
+	TicketStatus_name = map[int32]string{
+		0: "TICKET_STATUS_UNSPECIFIED",
+		1: "TICKET_STATUS_ACTIVE",
+		2: "TICKET_STATUS_EXITED",
+		3: "TICKET_STATUS_VOIDED",
+	}
+	TicketStatus_value = map[string]int32{
+		"TICKET_STATUS_UNSPECIFIED": 0,
+		"TICKET_STATUS_ACTIVE":      1,
+		"TICKET_STATUS_EXITED":      2,
+		"TICKET_STATUS_VOIDED":      3,
+	}
+)
+
+func (x TicketStatus) Enum() *TicketStatus {
+	p := new(TicketStatus)
+	*p = x
+	return p
+}
+
+func (x TicketStatus) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (TicketStatus) Descriptor() protoreflect.EnumDescriptor {
+	return file_parking_proto_enumTypes[0].Descriptor()
+}
+
+func (TicketStatus) Type() protoreflect.EnumType {
+	return &file_parking_proto_enumTypes[0]
+}
+
+func (x TicketStatus) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use TicketStatus.Descriptor instead.
+func (TicketStatus) EnumDescriptor() ([]byte, []int) {
+	return file_parking_proto_rawDescGZIP(), []int{0}
+}
+
+type ParkRequest struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	NumberPlate string                 `protobuf:"bytes,1,opt,name=number_plate,json=numberPlate,proto3" json:"number_plate,omitempty"`
+	// car, motorcycle, truck or a registered custom type
+	VehicleType string `protobuf:"bytes,2,opt,name=vehicle_type,json=vehicleType,proto3" json:"vehicle_type,omitempty"`
+	// Adjacent spots a truck needs; defaults to 1
+	Spots int32 `protobuf:"varint,3,opt,name=spots,proto3" json:"spots,omitempty"`
+	// Spot attributes the spot must have, e.g. "covered" or "ev-charger"
+	Require []string `protobuf:"bytes,4,rep,name=require,proto3" json:"require,omitempty"`
+	// Spot attributes the spot should have if one is free
+	Prefer        []string `protobuf:"bytes,5,rep,name=prefer,proto3" json:"prefer,omitempty"`
+	MinChargerKw  float64  `protobuf:"fixed64,6,opt,name=min_charger_kw,json=minChargerKw,proto3" json:"min_charger_kw,omitempty"`
+	EntryGateId   string   `protobuf:"bytes,7,opt,name=entry_gate_id,json=entryGateId,proto3" json:"entry_gate_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ParkRequest) Reset() {
+	*x = ParkRequest{}
+	mi := &file_parking_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ParkRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ParkRequest) ProtoMessage() {}
+
+func (x *ParkRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_parking_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ParkRequest.ProtoReflect.Descriptor instead.
+func (*ParkRequest) Descriptor() ([]byte, []int) {
+	return file_parking_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *ParkRequest) GetNumberPlate() string {
+	if x != nil {
+		return x.NumberPlate
+	}
+	return ""
+}
+
+func (x *ParkRequest) GetVehicleType() string {
+	if x != nil {
+		return x.VehicleType
+	}
+	return ""
+}
+
+func (x *ParkRequest) GetSpots() int32 {
+	if x != nil {
+		return x.Spots
+	}
+	return 0
+}
+
+func (x *ParkRequest) GetRequire() []string {
+	if x != nil {
+		return x.Require
+	}
+	return nil
+}
+
+func (x *ParkRequest) GetPrefer() []string {
+	if x != nil {
+		return x.Prefer
+	}
+	return nil
+}
+
+func (x *ParkRequest) GetMinChargerKw() float64 {
+	if x != nil {
+		return x.MinChargerKw
+	}
+	return 0
+}
+
+func (x *ParkRequest) GetEntryGateId() string {
+	if x != nil {
+		return x.EntryGateId
+	}
+	return ""
+}
+
+type ParkResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Ticket        *Ticket                `protobuf:"bytes,1,opt,name=ticket,proto3" json:"ticket,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ParkResponse) Reset() {
+	*x = ParkResponse{}
+	mi := &file_parking_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ParkResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ParkResponse) ProtoMessage() {}
+
+func (x *ParkResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_parking_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ParkResponse.ProtoReflect.Descriptor instead.
+func (*ParkResponse) Descriptor() ([]byte, []int) {
+	return file_parking_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *ParkResponse) GetTicket() *Ticket {
+	if x != nil {
+		return x.Ticket
+	}
+	return nil
+}
+
+type PayRequest struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	TicketId string                 `protobuf:"bytes,1,opt,name=ticket_id,json=ticketId,proto3" json:"ticket_id,omitempty"`
+	// cash, card or wallet; the lot must have a processor for the method
+	Method string `protobuf:"bytes,2,opt,name=method,proto3" json:"method,omitempty"`
+	// Cents; 0 pays the full balance
+	Amount        int64 `protobuf:"varint,3,opt,name=amount,proto3" json:"amount,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PayRequest) Reset() {
+	*x = PayRequest{}
+	mi := &file_parking_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PayRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PayRequest) ProtoMessage() {}
+
+func (x *PayRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_parking_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PayRequest.ProtoReflect.Descriptor instead.
+func (*PayRequest) Descriptor() ([]byte, []int) {
+	return file_parking_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *PayRequest) GetTicketId() string {
+	if x != nil {
+		return x.TicketId
+	}
+	return ""
+}
+
+func (x *PayRequest) GetMethod() string {
+	if x != nil {
+		return x.Method
+	}
+	return ""
+}
+
+func (x *PayRequest) GetAmount() int64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+type PayResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Payment       *Payment               `protobuf:"bytes,1,opt,name=payment,proto3" json:"payment,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PayResponse) Reset() {
+	*x = PayResponse{}
+	mi := &file_parking_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PayResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PayResponse) ProtoMessage() {}
+
+func (x *PayResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_parking_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PayResponse.ProtoReflect.Descriptor instead.
+func (*PayResponse) Descriptor() ([]byte, []int) {
+	return file_parking_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *PayResponse) GetPayment() *Payment {
+	if x != nil {
+		return x.Payment
+	}
+	return nil
+}
+
+type UnparkRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TicketId      string                 `protobuf:"bytes,1,opt,name=ticket_id,json=ticketId,proto3" json:"ticket_id,omitempty"`
+	ExitGateId    string                 `protobuf:"bytes,2,opt,name=exit_gate_id,json=exitGateId,proto3" json:"exit_gate_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UnparkRequest) Reset() {
+	*x = UnparkRequest{}
+	mi := &file_parking_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UnparkRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UnparkRequest) ProtoMessage() {}
+
+func (x *UnparkRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_parking_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UnparkRequest.ProtoReflect.Descriptor instead.
+func (*UnparkRequest) Descriptor() ([]byte, []int) {
+	return file_parking_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *UnparkRequest) GetTicketId() string {
+	if x != nil {
+		return x.TicketId
+	}
+	return ""
+}
+
+func (x *UnparkRequest) GetExitGateId() string {
+	if x != nil {
+		return x.ExitGateId
+	}
+	return ""
+}
+
+type UnparkResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Ticket        *Ticket                `protobuf:"bytes,1,opt,name=ticket,proto3" json:"ticket,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UnparkResponse) Reset() {
+	*x = UnparkResponse{}
+	mi := &file_parking_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UnparkResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UnparkResponse) ProtoMessage() {}
+
+func (x *UnparkResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_parking_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UnparkResponse.ProtoReflect.Descriptor instead.
+func (*UnparkResponse) Descriptor() ([]byte, []int) {
+	return file_parking_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *UnparkResponse) GetTicket() *Ticket {
+	if x != nil {
+		return x.Ticket
+	}
+	return nil
+}
+
+type GetStatusRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetStatusRequest) Reset() {
+	*x = GetStatusRequest{}
+	mi := &file_parking_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetStatusRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetStatusRequest) ProtoMessage() {}
+
+func (x *GetStatusRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_parking_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetStatusRequest.ProtoReflect.Descriptor instead.
+func (*GetStatusRequest) Descriptor() ([]byte, []int) {
+	return file_parking_proto_rawDescGZIP(), []int{6}
+}
+
+type GetStatusResponse struct {
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	Floors             []*FloorStatus         `protobuf:"bytes,1,rep,name=floors,proto3" json:"floors,omitempty"`
+	TotalActiveTickets int32                  `protobuf:"varint,2,opt,name=total_active_tickets,json=totalActiveTickets,proto3" json:"total_active_tickets,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *GetStatusResponse) Reset() {
+	*x = GetStatusResponse{}
+	mi := &file_parking_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetStatusResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetStatusResponse) ProtoMessage() {}
+
+func (x *GetStatusResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_parking_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetStatusResponse.ProtoReflect.Descriptor instead.
+func (*GetStatusResponse) Descriptor() ([]byte, []int) {
+	return file_parking_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *GetStatusResponse) GetFloors() []*FloorStatus {
+	if x != nil {
+		return x.Floors
+	}
+	return nil
+}
+
+func (x *GetStatusResponse) GetTotalActiveTickets() int32 {
+	if x != nil {
+		return x.TotalActiveTickets
+	}
+	return 0
+}
+
+type WatchAvailabilityRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Floors to watch; empty watches every floor
+	FloorIds      []int32 `protobuf:"varint,1,rep,packed,name=floor_ids,json=floorIds,proto3" json:"floor_ids,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchAvailabilityRequest) Reset() {
+	*x = WatchAvailabilityRequest{}
+	mi := &file_parking_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchAvailabilityRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchAvailabilityRequest) ProtoMessage() {}
+
+func (x *WatchAvailabilityRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_parking_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchAvailabilityRequest.ProtoReflect.Descriptor instead.
+func (*WatchAvailabilityRequest) Descriptor() ([]byte, []int) {
+	return file_parking_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *WatchAvailabilityRequest) GetFloorIds() []int32 {
+	if x != nil {
+		return x.FloorIds
+	}
+	return nil
+}
+
+type AvailabilityUpdate struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Time          *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=time,proto3" json:"time,omitempty"`
+	FloorId       int32                  `protobuf:"varint,2,opt,name=floor_id,json=floorId,proto3" json:"floor_id,omitempty"`
+	SpotType      string                 `protobuf:"bytes,3,opt,name=spot_type,json=spotType,proto3" json:"spot_type,omitempty"`
+	Vacant        int32                  `protobuf:"varint,4,opt,name=vacant,proto3" json:"vacant,omitempty"`
+	Total         int32                  `protobuf:"varint,5,opt,name=total,proto3" json:"total,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AvailabilityUpdate) Reset() {
+	*x = AvailabilityUpdate{}
+	mi := &file_parking_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AvailabilityUpdate) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AvailabilityUpdate) ProtoMessage() {}
+
+func (x *AvailabilityUpdate) ProtoReflect() protoreflect.Message {
+	mi := &file_parking_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AvailabilityUpdate.ProtoReflect.Descriptor instead.
+func (*AvailabilityUpdate) Descriptor() ([]byte, []int) {
+	return file_parking_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *AvailabilityUpdate) GetTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Time
+	}
+	return nil
+}
+
+func (x *AvailabilityUpdate) GetFloorId() int32 {
+	if x != nil {
+		return x.FloorId
+	}
+	return 0
+}
+
+func (x *AvailabilityUpdate) GetSpotType() string {
+	if x != nil {
+		return x.SpotType
+	}
+	return ""
+}
+
+func (x *AvailabilityUpdate) GetVacant() int32 {
+	if x != nil {
+		return x.Vacant
+	}
+	return 0
+}
+
+func (x *AvailabilityUpdate) GetTotal() int32 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+type Ticket struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	Id          string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Status      TicketStatus           `protobuf:"varint,2,opt,name=status,proto3,enum=parking.v1.TicketStatus" json:"status,omitempty"`
+	NumberPlate string                 `protobuf:"bytes,3,opt,name=number_plate,json=numberPlate,proto3" json:"number_plate,omitempty"`
+	VehicleType string                 `protobuf:"bytes,4,opt,name=vehicle_type,json=vehicleType,proto3" json:"vehicle_type,omitempty"`
+	FloorId     int32                  `protobuf:"varint,5,opt,name=floor_id,json=floorId,proto3" json:"floor_id,omitempty"`
+	SpotType    string                 `protobuf:"bytes,6,opt,name=spot_type,json=spotType,proto3" json:"spot_type,omitempty"`
+	SpotIds     []int32                `protobuf:"varint,7,rep,packed,name=spot_ids,json=spotIds,proto3" json:"spot_ids,omitempty"`
+	EntryTime   *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=entry_time,json=entryTime,proto3" json:"entry_time,omitempty"`
+	// Unset while the ticket is active
+	ExitTime *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=exit_time,json=exitTime,proto3" json:"exit_time,omitempty"`
+	// Cents
+	PricePerHour int64 `protobuf:"varint,10,opt,name=price_per_hour,json=pricePerHour,proto3" json:"price_per_hour,omitempty"`
+	PaidAmount   int64 `protobuf:"varint,11,opt,name=paid_amount,json=paidAmount,proto3" json:"paid_amount,omitempty"`
+	// What is due if the vehicle left now; only set on active tickets
+	AmountDue     int64  `protobuf:"varint,12,opt,name=amount_due,json=amountDue,proto3" json:"amount_due,omitempty"`
+	Fee           int64  `protobuf:"varint,13,opt,name=fee,proto3" json:"fee,omitempty"`
+	ReplacedById  string `protobuf:"bytes,14,opt,name=replaced_by_id,json=replacedById,proto3" json:"replaced_by_id,omitempty"`
+	EntryGateId   string `protobuf:"bytes,15,opt,name=entry_gate_id,json=entryGateId,proto3" json:"entry_gate_id,omitempty"`
+	ExitGateId    string `protobuf:"bytes,16,opt,name=exit_gate_id,json=exitGateId,proto3" json:"exit_gate_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Ticket) Reset() {
+	*x = Ticket{}
+	mi := &file_parking_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Ticket) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Ticket) ProtoMessage() {}
+
+func (x *Ticket) ProtoReflect() protoreflect.Message {
+	mi := &file_parking_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Ticket.ProtoReflect.Descriptor instead.
+func (*Ticket) Descriptor() ([]byte, []int) {
+	return file_parking_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *Ticket) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Ticket) GetStatus() TicketStatus {
+	if x != nil {
+		return x.Status
+	}
+	return TicketStatus_TICKET_STATUS_UNSPECIFIED
+}
+
+func (x *Ticket) GetNumberPlate() string {
+	if x != nil {
+		return x.NumberPlate
+	}
+	return ""
+}
+
+func (x *Ticket) GetVehicleType() string {
+	if x != nil {
+		return x.VehicleType
+	}
+	return ""
+}
+
+func (x *Ticket) GetFloorId() int32 {
+	if x != nil {
+		return x.FloorId
+	}
+	return 0
+}
+
+func (x *Ticket) GetSpotType() string {
+	if x != nil {
+		return x.SpotType
+	}
+	return ""
+}
+
+func (x *Ticket) GetSpotIds() []int32 {
+	if x != nil {
+		return x.SpotIds
+	}
+	return nil
+}
+
+func (x *Ticket) GetEntryTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.EntryTime
+	}
+	return nil
+}
+
+func (x *Ticket) GetExitTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ExitTime
+	}
+	return nil
+}
+
+func (x *Ticket) GetPricePerHour() int64 {
+	if x != nil {
+		return x.PricePerHour
+	}
+	return 0
+}
+
+func (x *Ticket) GetPaidAmount() int64 {
+	if x != nil {
+		return x.PaidAmount
+	}
+	return 0
+}
+
+func (x *Ticket) GetAmountDue() int64 {
+	if x != nil {
+		return x.AmountDue
+	}
+	return 0
+}
+
+func (x *Ticket) GetFee() int64 {
+	if x != nil {
+		return x.Fee
+	}
+	return 0
+}
+
+func (x *Ticket) GetReplacedById() string {
+	if x != nil {
+		return x.ReplacedById
+	}
+	return ""
+}
+
+func (x *Ticket) GetEntryGateId() string {
+	if x != nil {
+		return x.EntryGateId
+	}
+	return ""
+}
+
+func (x *Ticket) GetExitGateId() string {
+	if x != nil {
+		return x.ExitGateId
+	}
+	return ""
+}
+
+type Payment struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Id       string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	TicketId string                 `protobuf:"bytes,2,opt,name=ticket_id,json=ticketId,proto3" json:"ticket_id,omitempty"`
+	// Cents
+	Amount        int64                  `protobuf:"varint,3,opt,name=amount,proto3" json:"amount,omitempty"`
+	Method        string                 `protobuf:"bytes,4,opt,name=method,proto3" json:"method,omitempty"`
+	Status        string                 `protobuf:"bytes,5,opt,name=status,proto3" json:"status,omitempty"`
+	Reference     string                 `protobuf:"bytes,6,opt,name=reference,proto3" json:"reference,omitempty"`
+	FailureReason string                 `protobuf:"bytes,7,opt,name=failure_reason,json=failureReason,proto3" json:"failure_reason,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Payment) Reset() {
+	*x = Payment{}
+	mi := &file_parking_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Payment) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Payment) ProtoMessage() {}
+
+func (x *Payment) ProtoReflect() protoreflect.Message {
+	mi := &file_parking_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Payment.ProtoReflect.Descriptor instead.
+func (*Payment) Descriptor() ([]byte, []int) {
+	return file_parking_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *Payment) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Payment) GetTicketId() string {
+	if x != nil {
+		return x.TicketId
+	}
+	return ""
+}
+
+func (x *Payment) GetAmount() int64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+func (x *Payment) GetMethod() string {
+	if x != nil {
+		return x.Method
+	}
+	return ""
+}
+
+func (x *Payment) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *Payment) GetReference() string {
+	if x != nil {
+		return x.Reference
+	}
+	return ""
+}
+
+func (x *Payment) GetFailureReason() string {
+	if x != nil {
+		return x.FailureReason
+	}
+	return ""
+}
+
+func (x *Payment) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+type FloorStatus struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	FloorId       int32                  `protobuf:"varint,1,opt,name=floor_id,json=floorId,proto3" json:"floor_id,omitempty"`
+	Closed        bool                   `protobuf:"varint,2,opt,name=closed,proto3" json:"closed,omitempty"`
+	Spots         []*SpotsSummary        `protobuf:"bytes,3,rep,name=spots,proto3" json:"spots,omitempty"`
+	Name          string                 `protobuf:"bytes,4,opt,name=name,proto3" json:"name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FloorStatus) Reset() {
+	*x = FloorStatus{}
+	mi := &file_parking_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FloorStatus) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FloorStatus) ProtoMessage() {}
+
+func (x *FloorStatus) ProtoReflect() protoreflect.Message {
+	mi := &file_parking_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FloorStatus.ProtoReflect.Descriptor instead.
+func (*FloorStatus) Descriptor() ([]byte, []int) {
+	return file_parking_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *FloorStatus) GetFloorId() int32 {
+	if x != nil {
+		return x.FloorId
+	}
+	return 0
+}
+
+func (x *FloorStatus) GetClosed() bool {
+	if x != nil {
+		return x.Closed
+	}
+	return false
+}
+
+func (x *FloorStatus) GetSpots() []*SpotsSummary {
+	if x != nil {
+		return x.Spots
+	}
+	return nil
+}
+
+func (x *FloorStatus) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+// SpotsSummary counts the spots of one kind on a floor by state
+type SpotsSummary struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SpotType      string                 `protobuf:"bytes,1,opt,name=spot_type,json=spotType,proto3" json:"spot_type,omitempty"`
+	Total         int32                  `protobuf:"varint,2,opt,name=total,proto3" json:"total,omitempty"`
+	Occupied      int32                  `protobuf:"varint,3,opt,name=occupied,proto3" json:"occupied,omitempty"`
+	Vacant        int32                  `protobuf:"varint,4,opt,name=vacant,proto3" json:"vacant,omitempty"`
+	Reserved      int32                  `protobuf:"varint,5,opt,name=reserved,proto3" json:"reserved,omitempty"`
+	OutOfService  int32                  `protobuf:"varint,6,opt,name=out_of_service,json=outOfService,proto3" json:"out_of_service,omitempty"`
+	Blocked       int32                  `protobuf:"varint,7,opt,name=blocked,proto3" json:"blocked,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SpotsSummary) Reset() {
+	*x = SpotsSummary{}
+	mi := &file_parking_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SpotsSummary) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SpotsSummary) ProtoMessage() {}
+
+func (x *SpotsSummary) ProtoReflect() protoreflect.Message {
+	mi := &file_parking_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SpotsSummary.ProtoReflect.Descriptor instead.
+func (*SpotsSummary) Descriptor() ([]byte, []int) {
+	return file_parking_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *SpotsSummary) GetSpotType() string {
+	if x != nil {
+		return x.SpotType
+	}
+	return ""
+}
+
+func (x *SpotsSummary) GetTotal() int32 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+func (x *SpotsSummary) GetOccupied() int32 {
+	if x != nil {
+		return x.Occupied
+	}
+	return 0
+}
+
+func (x *SpotsSummary) GetVacant() int32 {
+	if x != nil {
+		return x.Vacant
+	}
+	return 0
+}
+
+func (x *SpotsSummary) GetReserved() int32 {
+	if x != nil {
+		return x.Reserved
+	}
+	return 0
+}
+
+func (x *SpotsSummary) GetOutOfService() int32 {
+	if x != nil {
+		return x.OutOfService
+	}
+	return 0
+}
+
+func (x *SpotsSummary) GetBlocked() int32 {
+	if x != nil {
+		return x.Blocked
+	}
+	return 0
+}
+
+var File_parking_proto protoreflect.FileDescriptor
+
+const file_parking_proto_rawDesc = "" +
+	"\n" +
+	"\rparking.proto\x12\n" +
+	"parking.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"\xe5\x01\n" +
+	"\vParkRequest\x12!\n" +
+	"\fnumber_plate\x18\x01 \x01(\tR\vnumberPlate\x12!\n" +
+	"\fvehicle_type\x18\x02 \x01(\tR\vvehicleType\x12\x14\n" +
+	"\x05spots\x18\x03 \x01(\x05R\x05spots\x12\x18\n" +
+	"\arequire\x18\x04 \x03(\tR\arequire\x12\x16\n" +
+	"\x06prefer\x18\x05 \x03(\tR\x06prefer\x12$\n" +
+	"\x0emin_charger_kw\x18\x06 \x01(\x01R\fminChargerKw\x12\"\n" +
+	"\rentry_gate_id\x18\a \x01(\tR\ventryGateId\":\n" +
+	"\fParkResponse\x12*\n" +
+	"\x06ticket\x18\x01 \x01(\v2\x12.parking.v1.TicketR\x06ticket\"Y\n" +
+	"\n" +
+	"PayRequest\x12\x1b\n" +
+	"\tticket_id\x18\x01 \x01(\tR\bticketId\x12\x16\n" +
+	"\x06method\x18\x02 \x01(\tR\x06method\x12\x16\n" +
+	"\x06amount\x18\x03 \x01(\x03R\x06amount\"<\n" +
+	"\vPayResponse\x12-\n" +
+	"\apayment\x18\x01 \x01(\v2\x13.parking.v1.PaymentR\apayment\"N\n" +
+	"\rUnparkRequest\x12\x1b\n" +
+	"\tticket_id\x18\x01 \x01(\tR\bticketId\x12 \n" +
+	"\fexit_gate_id\x18\x02 \x01(\tR\n" +
+	"exitGateId\"<\n" +
+	"\x0eUnparkResponse\x12*\n" +
+	"\x06ticket\x18\x01 \x01(\v2\x12.parking.v1.TicketR\x06ticket\"\x12\n" +
+	"\x10GetStatusRequest\"v\n" +
+	"\x11GetStatusResponse\x12/\n" +
+	"\x06floors\x18\x01 \x03(\v2\x17.parking.v1.FloorStatusR\x06floors\x120\n" +
+	"\x14total_active_tickets\x18\x02 \x01(\x05R\x12totalActiveTickets\"7\n" +
+	"\x18WatchAvailabilityRequest\x12\x1b\n" +
+	"\tfloor_ids\x18\x01 \x03(\x05R\bfloorIds\"\xaa\x01\n" +
+	"\x12AvailabilityUpdate\x12.\n" +
+	"\x04time\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampR\x04time\x12\x19\n" +
+	"\bfloor_id\x18\x02 \x01(\x05R\afloorId\x12\x1b\n" +
+	"\tspot_type\x18\x03 \x01(\tR\bspotType\x12\x16\n" +
+	"\x06vacant\x18\x04 \x01(\x05R\x06vacant\x12\x14\n" +
+	"\x05total\x18\x05 \x01(\x05R\x05total\"\xbb\x04\n" +
+	"\x06Ticket\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x120\n" +
+	"\x06status\x18\x02 \x01(\x0e2\x18.parking.v1.TicketStatusR\x06status\x12!\n" +
+	"\fnumber_plate\x18\x03 \x01(\tR\vnumberPlate\x12!\n" +
+	"\fvehicle_type\x18\x04 \x01(\tR\vvehicleType\x12\x19\n" +
+	"\bfloor_id\x18\x05 \x01(\x05R\afloorId\x12\x1b\n" +
+	"\tspot_type\x18\x06 \x01(\tR\bspotType\x12\x19\n" +
+	"\bspot_ids\x18\a \x03(\x05R\aspotIds\x129\n" +
+	"\n" +
+	"entry_time\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\tentryTime\x127\n" +
+	"\texit_time\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\bexitTime\x12$\n" +
+	"\x0eprice_per_hour\x18\n" +
+	" \x01(\x03R\fpricePerHour\x12\x1f\n" +
+	"\vpaid_amount\x18\v \x01(\x03R\n" +
+	"paidAmount\x12\x1d\n" +
+	"\n" +
+	"amount_due\x18\f \x01(\x03R\tamountDue\x12\x10\n" +
+	"\x03fee\x18\r \x01(\x03R\x03fee\x12$\n" +
+	"\x0ereplaced_by_id\x18\x0e \x01(\tR\freplacedById\x12\"\n" +
+	"\rentry_gate_id\x18\x0f \x01(\tR\ventryGateId\x12 \n" +
+	"\fexit_gate_id\x18\x10 \x01(\tR\n" +
+	"exitGateId\"\xfe\x01\n" +
+	"\aPayment\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1b\n" +
+	"\tticket_id\x18\x02 \x01(\tR\bticketId\x12\x16\n" +
+	"\x06amount\x18\x03 \x01(\x03R\x06amount\x12\x16\n" +
+	"\x06method\x18\x04 \x01(\tR\x06method\x12\x16\n" +
+	"\x06status\x18\x05 \x01(\tR\x06status\x12\x1c\n" +
+	"\treference\x18\x06 \x01(\tR\treference\x12%\n" +
+	"\x0efailure_reason\x18\a \x01(\tR\rfailureReason\x129\n" +
+	"\n" +
+	"created_at\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\"\x84\x01\n" +
+	"\vFloorStatus\x12\x19\n" +
+	"\bfloor_id\x18\x01 \x01(\x05R\afloorId\x12\x16\n" +
+	"\x06closed\x18\x02 \x01(\bR\x06closed\x12.\n" +
+	"\x05spots\x18\x03 \x03(\v2\x18.parking.v1.SpotsSummaryR\x05spots\x12\x12\n" +
+	"\x04name\x18\x04 \x01(\tR\x04name\"\xd1\x01\n" +
+	"\fSpotsSummary\x12\x1b\n" +
+	"\tspot_type\x18\x01 \x01(\tR\bspotType\x12\x14\n" +
+	"\x05total\x18\x02 \x01(\x05R\x05total\x12\x1a\n" +
+	"\boccupied\x18\x03 \x01(\x05R\boccupied\x12\x16\n" +
+	"\x06vacant\x18\x04 \x01(\x05R\x06vacant\x12\x1a\n" +
+	"\breserved\x18\x05 \x01(\x05R\breserved\x12$\n" +
+	"\x0eout_of_service\x18\x06 \x01(\x05R\foutOfService\x12\x18\n" +
+	"\ablocked\x18\a \x01(\x05R\ablocked*{\n" +
+	"\fTicketStatus\x12\x1d\n" +
+	"\x19TICKET_STATUS_UNSPECIFIED\x10\x00\x12\x18\n" +
+	"\x14TICKET_STATUS_ACTIVE\x10\x01\x12\x18\n" +
+	"\x14TICKET_STATUS_EXITED\x10\x02\x12\x18\n" +
+	"\x14TICKET_STATUS_VOIDED\x10\x032\xe7\x02\n" +
+	"\n" +
+	"ParkingLot\x129\n" +
+	"\x04Park\x12\x17.parking.v1.ParkRequest\x1a\x18.parking.v1.ParkResponse\x126\n" +
+	"\x03Pay\x12\x16.parking.v1.PayRequest\x1a\x17.parking.v1.PayResponse\x12?\n" +
+	"\x06Unpark\x12\x19.parking.v1.UnparkRequest\x1a\x1a.parking.v1.UnparkResponse\x12H\n" +
+	"\tGetStatus\x12\x1c.parking.v1.GetStatusRequest\x1a\x1d.parking.v1.GetStatusResponse\x12[\n" +
+	"\x11WatchAvailability\x12$.parking.v1.WatchAvailabilityRequest\x1a\x1e.parking.v1.AvailabilityUpdate0\x01B&Z$parkinglot/proto/parkingpb;parkingpbb\x06proto3"
+
+var (
+	file_parking_proto_rawDescOnce sync.Once
+	file_parking_proto_rawDescData []byte
+)
+
+func file_parking_proto_rawDescGZIP() []byte {
+	file_parking_proto_rawDescOnce.Do(func() {
+		file_parking_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_parking_proto_rawDesc), len(file_parking_proto_rawDesc)))
+	})
+	return file_parking_proto_rawDescData
+}
+
+var file_parking_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_parking_proto_msgTypes = make([]protoimpl.MessageInfo, 14)
+var file_parking_proto_goTypes = []any{
+	(TicketStatus)(0),                // 0: parking.v1.TicketStatus
+	(*ParkRequest)(nil),              // 1: parking.v1.ParkRequest
+	(*ParkResponse)(nil),             // 2: parking.v1.ParkResponse
+	(*PayRequest)(nil),               // 3: parking.v1.PayRequest
+	(*PayResponse)(nil),              // 4: parking.v1.PayResponse
+	(*UnparkRequest)(nil),            // 5: parking.v1.UnparkRequest
+	(*UnparkResponse)(nil),           // 6: parking.v1.UnparkResponse
+	(*GetStatusRequest)(nil),         // 7: parking.v1.GetStatusRequest
+	(*GetStatusResponse)(nil),        // 8: parking.v1.GetStatusResponse
+	(*WatchAvailabilityRequest)(nil), // 9: parking.v1.WatchAvailabilityRequest
+	(*AvailabilityUpdate)(nil),       // 10: parking.v1.AvailabilityUpdate
+	(*Ticket)(nil),                   // 11: parking.v1.Ticket
+	(*Payment)(nil),                  // 12: parking.v1.Payment
+	(*FloorStatus)(nil),              // 13: parking.v1.FloorStatus
+	(*SpotsSummary)(nil),             // 14: parking.v1.SpotsSummary
+	(*timestamppb.Timestamp)(nil),    // 15: google.protobuf.Timestamp
+}
+var file_parking_proto_depIdxs = []int32{
+	11, // 0: parking.v1.ParkResponse.ticket:type_name -> parking.v1.Ticket
+	12, // 1: parking.v1.PayResponse.payment:type_name -> parking.v1.Payment
+	11, // 2: parking.v1.UnparkResponse.ticket:type_name -> parking.v1.Ticket
+	13, // 3: parking.v1.GetStatusResponse.floors:type_name -> parking.v1.FloorStatus
+	15, // 4: parking.v1.AvailabilityUpdate.time:type_name -> google.protobuf.Timestamp
+	0,  // 5: parking.v1.Ticket.status:type_name -> parking.v1.TicketStatus
+	15, // 6: parking.v1.Ticket.entry_time:type_name -> google.protobuf.Timestamp
+	15, // 7: parking.v1.Ticket.exit_time:type_name -> google.protobuf.Timestamp
+	15, // 8: parking.v1.Payment.created_at:type_name -> google.protobuf.Timestamp
+	14, // 9: parking.v1.FloorStatus.spots:type_name -> parking.v1.SpotsSummary
+	1,  // 10: parking.v1.ParkingLot.Park:input_type -> parking.v1.ParkRequest
+	3,  // 11: parking.v1.ParkingLot.Pay:input_type -> parking.v1.PayRequest
+	5,  // 12: parking.v1.ParkingLot.Unpark:input_type -> parking.v1.UnparkRequest
+	7,  // 13: parking.v1.ParkingLot.GetStatus:input_type -> parking.v1.GetStatusRequest
+	9,  // 14: parking.v1.ParkingLot.WatchAvailability:input_type -> parking.v1.WatchAvailabilityRequest
+	2,  // 15: parking.v1.ParkingLot.Park:output_type -> parking.v1.ParkResponse
+	4,  // 16: parking.v1.ParkingLot.Pay:output_type -> parking.v1.PayResponse
+	6,  // 17: parking.v1.ParkingLot.Unpark:output_type -> parking.v1.UnparkResponse
+	8,  // 18: parking.v1.ParkingLot.GetStatus:output_type -> parking.v1.GetStatusResponse
+	10, // 19: parking.v1.ParkingLot.WatchAvailability:output_type -> parking.v1.AvailabilityUpdate
+	15, // [15:20] is the sub-list for method output_type
+	10, // [10:15] is the sub-list for method input_type
+	10, // [10:10] is the sub-list for extension type_name
+	10, // [10:10] is the sub-list for extension extendee
+	0,  // [0:10] is the sub-list for field type_name
+}
+
+func init() { file_parking_proto_init() }
+func file_parking_proto_init() {
+	if File_parking_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_parking_proto_rawDesc), len(file_parking_proto_rawDesc)),
+			NumEnums:      1,
+			NumMessages:   14,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_parking_proto_goTypes,
+		DependencyIndexes: file_parking_proto_depIdxs,
+		EnumInfos:         file_parking_proto_enumTypes,
+		MessageInfos:      file_parking_proto_msgTypes,
+	}.Build()
+	File_parking_proto = out.File
+	file_parking_proto_goTypes = nil
+	file_parking_proto_depIdxs = nil
+}
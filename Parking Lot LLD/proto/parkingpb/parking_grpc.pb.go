@@ -0,0 +1,300 @@
+// Parking operations for kiosks and gate controllers
+//
+// Errors are reported with gRPC status codes derived from the lot's errors:
+//   INVALID_ARGUMENT     malformed request, unknown vehicle type, floor, gate or payment method
+//   NOT_FOUND            unknown ticket, or a vehicle that is not parked
+//   ALREADY_EXISTS       the vehicle is already parked
+//   RESOURCE_EXHAUSTED   no spot fits the vehicle
+//   FAILED_PRECONDITION  the ticket is voided, has nothing to pay or must be paid before exit
+//   ABORTED              the payment was declined
+//   INTERNAL             anything else
+
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: parking.proto
+
+package parkingpb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	ParkingLot_Park_FullMethodName              = "/parking.v1.ParkingLot/Park"
+	ParkingLot_Pay_FullMethodName               = "/parking.v1.ParkingLot/Pay"
+	ParkingLot_Unpark_FullMethodName            = "/parking.v1.ParkingLot/Unpark"
+	ParkingLot_GetStatus_FullMethodName         = "/parking.v1.ParkingLot/GetStatus"
+	ParkingLot_WatchAvailability_FullMethodName = "/parking.v1.ParkingLot/WatchAvailability"
+)
+
+// ParkingLotClient is the client API for ParkingLot service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type ParkingLotClient interface {
+	// Park finds spots for a vehicle and issues its ticket
+	Park(ctx context.Context, in *ParkRequest, opts ...grpc.CallOption) (*ParkResponse, error)
+	// Pay pays toward a ticket; a zero amount pays the full balance
+	Pay(ctx context.Context, in *PayRequest, opts ...grpc.CallOption) (*PayResponse, error)
+	// Unpark releases a paid ticket's vehicle
+	Unpark(ctx context.Context, in *UnparkRequest, opts ...grpc.CallOption) (*UnparkResponse, error)
+	// GetStatus reports the occupancy of every floor
+	GetStatus(ctx context.Context, in *GetStatusRequest, opts ...grpc.CallOption) (*GetStatusResponse, error)
+	// WatchAvailability streams the vacant spot count of every watched floor and spot kind, then
+	// an update each time one of them changes
+	WatchAvailability(ctx context.Context, in *WatchAvailabilityRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[AvailabilityUpdate], error)
+}
+
+type parkingLotClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewParkingLotClient(cc grpc.ClientConnInterface) ParkingLotClient {
+	return &parkingLotClient{cc}
+}
+
+func (c *parkingLotClient) Park(ctx context.Context, in *ParkRequest, opts ...grpc.CallOption) (*ParkResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ParkResponse)
+	err := c.cc.Invoke(ctx, ParkingLot_Park_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *parkingLotClient) Pay(ctx context.Context, in *PayRequest, opts ...grpc.CallOption) (*PayResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PayResponse)
+	err := c.cc.Invoke(ctx, ParkingLot_Pay_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *parkingLotClient) Unpark(ctx context.Context, in *UnparkRequest, opts ...grpc.CallOption) (*UnparkResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UnparkResponse)
+	err := c.cc.Invoke(ctx, ParkingLot_Unpark_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *parkingLotClient) GetStatus(ctx context.Context, in *GetStatusRequest, opts ...grpc.CallOption) (*GetStatusResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetStatusResponse)
+	err := c.cc.Invoke(ctx, ParkingLot_GetStatus_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *parkingLotClient) WatchAvailability(ctx context.Context, in *WatchAvailabilityRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[AvailabilityUpdate], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &ParkingLot_ServiceDesc.Streams[0], ParkingLot_WatchAvailability_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchAvailabilityRequest, AvailabilityUpdate]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type ParkingLot_WatchAvailabilityClient = grpc.ServerStreamingClient[AvailabilityUpdate]
+
+// ParkingLotServer is the server API for ParkingLot service.
+// All implementations must embed UnimplementedParkingLotServer
+// for forward compatibility.
+type ParkingLotServer interface {
+	// Park finds spots for a vehicle and issues its ticket
+	Park(context.Context, *ParkRequest) (*ParkResponse, error)
+	// Pay pays toward a ticket; a zero amount pays the full balance
+	Pay(context.Context, *PayRequest) (*PayResponse, error)
+	// Unpark releases a paid ticket's vehicle
+	Unpark(context.Context, *UnparkRequest) (*UnparkResponse, error)
+	// GetStatus reports the occupancy of every floor
+	GetStatus(context.Context, *GetStatusRequest) (*GetStatusResponse, error)
+	// WatchAvailability streams the vacant spot count of every watched floor and spot kind, then
+	// an update each time one of them changes
+	WatchAvailability(*WatchAvailabilityRequest, grpc.ServerStreamingServer[AvailabilityUpdate]) error
+	mustEmbedUnimplementedParkingLotServer()
+}
+
+// UnimplementedParkingLotServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedParkingLotServer struct{}
+
+func (UnimplementedParkingLotServer) Park(context.Context, *ParkRequest) (*ParkResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Park not implemented")
+}
+func (UnimplementedParkingLotServer) Pay(context.Context, *PayRequest) (*PayResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Pay not implemented")
+}
+func (UnimplementedParkingLotServer) Unpark(context.Context, *UnparkRequest) (*UnparkResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Unpark not implemented")
+}
+func (UnimplementedParkingLotServer) GetStatus(context.Context, *GetStatusRequest) (*GetStatusResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetStatus not implemented")
+}
+func (UnimplementedParkingLotServer) WatchAvailability(*WatchAvailabilityRequest, grpc.ServerStreamingServer[AvailabilityUpdate]) error {
+	return status.Errorf(codes.Unimplemented, "method WatchAvailability not implemented")
+}
+func (UnimplementedParkingLotServer) mustEmbedUnimplementedParkingLotServer() {}
+func (UnimplementedParkingLotServer) testEmbeddedByValue()                    {}
+
+// UnsafeParkingLotServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to ParkingLotServer will
+// result in compilation errors.
+type UnsafeParkingLotServer interface {
+	mustEmbedUnimplementedParkingLotServer()
+}
+
+func RegisterParkingLotServer(s grpc.ServiceRegistrar, srv ParkingLotServer) {
+	// If the following call pancis, it indicates UnimplementedParkingLotServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&ParkingLot_ServiceDesc, srv)
+}
+
+func _ParkingLot_Park_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ParkRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ParkingLotServer).Park(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ParkingLot_Park_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ParkingLotServer).Park(ctx, req.(*ParkRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ParkingLot_Pay_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PayRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ParkingLotServer).Pay(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ParkingLot_Pay_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ParkingLotServer).Pay(ctx, req.(*PayRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ParkingLot_Unpark_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UnparkRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ParkingLotServer).Unpark(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ParkingLot_Unpark_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ParkingLotServer).Unpark(ctx, req.(*UnparkRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ParkingLot_GetStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ParkingLotServer).GetStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ParkingLot_GetStatus_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ParkingLotServer).GetStatus(ctx, req.(*GetStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ParkingLot_WatchAvailability_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchAvailabilityRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ParkingLotServer).WatchAvailability(m, &grpc.GenericServerStream[WatchAvailabilityRequest, AvailabilityUpdate]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type ParkingLot_WatchAvailabilityServer = grpc.ServerStreamingServer[AvailabilityUpdate]
+
+// ParkingLot_ServiceDesc is the grpc.ServiceDesc for ParkingLot service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var ParkingLot_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "parking.v1.ParkingLot",
+	HandlerType: (*ParkingLotServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Park",
+			Handler:    _ParkingLot_Park_Handler,
+		},
+		{
+			MethodName: "Pay",
+			Handler:    _ParkingLot_Pay_Handler,
+		},
+		{
+			MethodName: "Unpark",
+			Handler:    _ParkingLot_Unpark_Handler,
+		},
+		{
+			MethodName: "GetStatus",
+			Handler:    _ParkingLot_GetStatus_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchAvailability",
+			Handler:       _ParkingLot_WatchAvailability_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "parking.proto",
+}
@@ -0,0 +1,178 @@
+package service
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+
+	"parkinglot/entities"
+)
+
+// AllocationRequest describes the spots a vehicle needs
+type AllocationRequest struct {
+	SpotType     entities.VehicleType      // kind of spot to allocate from
+	Spots        int                       // number of adjacent spots needed (1 for regular vehicles)
+	Requirements entities.SpotRequirements // attributes every chosen spot must have
+}
+
+// SpotAllocationStrategy decides where a vehicle should be parked
+// The service calls it while holding its lock, so implementations only need to pick a spot, not occupy it
+type SpotAllocationStrategy interface {
+	// SelectSpot returns the floor and the first spot ID of a run of req.Spots adjacent vacant spots
+	// Returns ErrParkingLotFull when no floor has room
+	SelectSpot(floors []*entities.ParkingSpace, req AllocationRequest) (*entities.ParkingSpace, int, error)
+}
+
+// spotIDs expands a run starting at firstSpotID into the list of spot IDs it covers
+func spotIDs(firstSpotID, length int) []int {
+	if length < 1 {
+		length = 1
+	}
+	ids := make([]int, length)
+	for i := range ids {
+		ids[i] = firstSpotID + i
+	}
+	return ids
+}
+
+// FillLowestFloorStrategy fills floors from bottom to top, taking the first vacant spot on each floor
+// This is the default strategy and matches the original behavior of ParkVehicle
+type FillLowestFloorStrategy struct{}
+
+// NewFillLowestFloorStrategy creates a new FillLowestFloorStrategy
+func NewFillLowestFloorStrategy() *FillLowestFloorStrategy {
+	return &FillLowestFloorStrategy{}
+}
+
+func (s *FillLowestFloorStrategy) SelectSpot(floors []*entities.ParkingSpace, req AllocationRequest) (*entities.ParkingSpace, int, error) {
+	for _, floor := range floors {
+		spotCollection := floor.GetSpotByVehicleType(req.SpotType)
+		if spotCollection == nil {
+			continue
+		}
+
+		spotID, err := spotCollection.FindMatchingRun(req.Spots, req.Requirements)
+		if err != nil {
+			continue // No spot on this floor, try next
+		}
+		return floor, spotID, nil
+	}
+	return nil, 0, ErrParkingLotFull
+}
+
+// SpreadEvenlyStrategy parks each vehicle on the floor with the most vacant spots for its type
+// Ties go to the lower floor
+type SpreadEvenlyStrategy struct{}
+
+// NewSpreadEvenlyStrategy creates a new SpreadEvenlyStrategy
+func NewSpreadEvenlyStrategy() *SpreadEvenlyStrategy {
+	return &SpreadEvenlyStrategy{}
+}
+
+func (s *SpreadEvenlyStrategy) SelectSpot(floors []*entities.ParkingSpace, req AllocationRequest) (*entities.ParkingSpace, int, error) {
+	var best *entities.ParkingSpace
+	bestSpotID, bestVacant := 0, 0
+	for _, floor := range floors {
+		spotCollection := floor.GetSpotByVehicleType(req.SpotType)
+		if spotCollection == nil {
+			continue
+		}
+		vacant := spotCollection.GetVacantCount()
+		if vacant < req.Spots || vacant <= bestVacant {
+			continue
+		}
+
+		spotID, err := spotCollection.FindMatchingRun(req.Spots, req.Requirements)
+		if err != nil {
+			continue // Enough vacant spots, but no adjacent run long enough
+		}
+		best, bestSpotID, bestVacant = floor, spotID, vacant
+	}
+	if best == nil {
+		return nil, 0, ErrParkingLotFull
+	}
+	return best, bestSpotID, nil
+}
+
+// NearestToEntranceStrategy picks the vacant spot closest to the entrance
+// Floors are ranked by their distance from EntranceFloor, and spot IDs are assumed to be
+// numbered outward from the ramp, so the lowest vacant ID on a floor is the nearest one
+type NearestToEntranceStrategy struct {
+	EntranceFloor int
+}
+
+// NewNearestToEntranceStrategy creates a new NearestToEntranceStrategy for the given entrance floor
+func NewNearestToEntranceStrategy(entranceFloor int) *NearestToEntranceStrategy {
+	return &NearestToEntranceStrategy{EntranceFloor: entranceFloor}
+}
+
+func (s *NearestToEntranceStrategy) SelectSpot(floors []*entities.ParkingSpace, req AllocationRequest) (*entities.ParkingSpace, int, error) {
+	var best *entities.ParkingSpace
+	bestSpotID, bestDistance := 0, -1
+	for _, floor := range floors {
+		spotCollection := floor.GetSpotByVehicleType(req.SpotType)
+		if spotCollection == nil {
+			continue
+		}
+
+		distance := floor.ID - s.EntranceFloor
+		if distance < 0 {
+			distance = -distance
+		}
+		if bestDistance != -1 && distance >= bestDistance {
+			continue
+		}
+
+		spotID, err := spotCollection.FindMatchingRun(req.Spots, req.Requirements)
+		if err != nil {
+			continue
+		}
+		best, bestSpotID, bestDistance = floor, spotID, distance
+	}
+	if best == nil {
+		return nil, 0, ErrParkingLotFull
+	}
+	return best, bestSpotID, nil
+}
+
+// RandomStrategy parks each vehicle on a randomly chosen floor that still has room for its type
+type RandomStrategy struct {
+	rng *rand.Rand
+	mu  sync.Mutex
+}
+
+// NewRandomStrategy creates a new RandomStrategy
+// A seed of 0 seeds the generator from the current time
+func NewRandomStrategy(seed int64) *RandomStrategy {
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	return &RandomStrategy{rng: rand.New(rand.NewSource(seed))}
+}
+
+func (s *RandomStrategy) SelectSpot(floors []*entities.ParkingSpace, req AllocationRequest) (*entities.ParkingSpace, int, error) {
+	type candidate struct {
+		floor  *entities.ParkingSpace
+		spotID int
+	}
+
+	candidates := make([]candidate, 0, len(floors))
+	for _, floor := range floors {
+		spotCollection := floor.GetSpotByVehicleType(req.SpotType)
+		if spotCollection == nil {
+			continue
+		}
+		if spotID, err := spotCollection.FindMatchingRun(req.Spots, req.Requirements); err == nil {
+			candidates = append(candidates, candidate{floor: floor, spotID: spotID})
+		}
+	}
+	if len(candidates) == 0 {
+		return nil, 0, ErrParkingLotFull
+	}
+
+	s.mu.Lock()
+	chosen := candidates[s.rng.Intn(len(candidates))]
+	s.mu.Unlock()
+
+	return chosen.floor, chosen.spotID, nil
+}
@@ -0,0 +1,40 @@
+package service
+
+import "time"
+
+// AuditAction names an operator action recorded in the audit log
+type AuditAction string
+
+const (
+	AuditLostTicket    AuditAction = "lost-ticket"
+	AuditSpotState     AuditAction = "spot-state"
+	AuditFloorClosed   AuditAction = "floor-closed"
+	AuditFloorReopened AuditAction = "floor-reopened"
+)
+
+// AuditEntry records an operator action on the lot
+type AuditEntry struct {
+	Time        time.Time   `json:"time"`
+	Operator    string      `json:"operator"`
+	Action      AuditAction `json:"action"`
+	TicketID    string      `json:"ticket_id"`
+	NumberPlate string      `json:"number_plate"`
+	Details     string      `json:"details"`
+}
+
+// audit appends an entry to the audit log, stamped with the current time unless it has one
+// Must be called with the lock held
+func (pls *ParkingLotService) audit(entry AuditEntry) {
+	if entry.Time.IsZero() {
+		entry.Time = pls.clock.Now()
+	}
+	pls.auditLog = append(pls.auditLog, entry)
+}
+
+// AuditLog returns every recorded operator action, oldest first
+func (pls *ParkingLotService) AuditLog() []AuditEntry {
+	pls.mu.RLock()
+	defer pls.mu.RUnlock()
+
+	return append([]AuditEntry(nil), pls.auditLog...)
+}
@@ -0,0 +1,107 @@
+package service
+
+import (
+	"sort"
+
+	"parkinglot/entities"
+)
+
+// CompatibilityRules lists, for each vehicle type, the spot kinds it may park in
+// Kinds are tried in order, so the first entry is the preferred kind and the rest are fallbacks
+type CompatibilityRules map[entities.VehicleType][]entities.VehicleType
+
+// DefaultCompatibility keeps every vehicle in the spot kinds registered for its type, with no fallback
+// Built-in types park in spots of their own kind
+func DefaultCompatibility() CompatibilityRules {
+	rules := make(CompatibilityRules)
+	for _, info := range entities.VehicleTypes() {
+		rules[info.Type] = ownSpotKinds(info)
+	}
+	return rules
+}
+
+// SizeFallbackCompatibility lets vehicles use spot kinds of a larger size class when their own kinds are full
+// For the built-in types this gives motorcycle -> car -> truck and car -> truck
+func SizeFallbackCompatibility() CompatibilityRules {
+	types := entities.VehicleTypes()
+
+	// Only types without SpotKinds have spots of their own, so only they can be fallbacks
+	spotKinds := make([]entities.VehicleTypeInfo, 0, len(types))
+	for _, info := range types {
+		if len(info.SpotKinds) == 0 {
+			spotKinds = append(spotKinds, info)
+		}
+	}
+	sort.SliceStable(spotKinds, func(i, j int) bool {
+		return spotKinds[i].SizeClass < spotKinds[j].SizeClass
+	})
+
+	rules := make(CompatibilityRules)
+	for _, info := range types {
+		kinds := ownSpotKinds(info)
+		largest := entities.SizeSmall
+		for _, kind := range kinds {
+			if kindInfo, ok := entities.LookupVehicleType(kind); ok && kindInfo.SizeClass > largest {
+				largest = kindInfo.SizeClass
+			}
+		}
+		if info.SizeClass > largest {
+			largest = info.SizeClass
+		}
+		for _, kind := range spotKinds {
+			if kind.SizeClass > largest {
+				kinds = append(kinds, kind.Type)
+			}
+		}
+		rules[info.Type] = kinds
+	}
+	return rules
+}
+
+// ownSpotKinds returns the spot kinds registered for a vehicle type
+func ownSpotKinds(info entities.VehicleTypeInfo) []entities.VehicleType {
+	if len(info.SpotKinds) > 0 {
+		return append([]entities.VehicleType(nil), info.SpotKinds...)
+	}
+	return []entities.VehicleType{info.Type}
+}
+
+// SpotKinds returns the spot kinds a vehicle type may use, in order of preference
+// A vehicle type without a rule uses the spot kinds registered for it
+func (r CompatibilityRules) SpotKinds(vehicleType entities.VehicleType) []entities.VehicleType {
+	if kinds, ok := r[vehicleType]; ok && len(kinds) > 0 {
+		return kinds
+	}
+	if info, ok := entities.LookupVehicleType(vehicleType); ok {
+		return ownSpotKinds(info)
+	}
+	return []entities.VehicleType{vehicleType}
+}
+
+// FallbackPricing decides which hourly rate applies when a vehicle parks in another kind of spot
+type FallbackPricing int
+
+const (
+	// VehicleRate charges the rate of the vehicle's own type
+	VehicleRate FallbackPricing = iota
+	// SpotRate charges the rate of the spot kind the vehicle actually occupies
+	SpotRate
+)
+
+// WithCompatibility sets the rules used to find fallback spots for a vehicle
+// Defaults to DefaultCompatibility
+func WithCompatibility(rules CompatibilityRules) Option {
+	return func(pls *ParkingLotService) {
+		if rules != nil {
+			pls.compatibility = rules
+		}
+	}
+}
+
+// WithFallbackPricing sets which rate is charged for fallback placements
+// Defaults to VehicleRate
+func WithFallbackPricing(policy FallbackPricing) Option {
+	return func(pls *ParkingLotService) {
+		pls.fallbackPricing = policy
+	}
+}
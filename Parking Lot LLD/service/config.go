@@ -0,0 +1,571 @@
+package service
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"parkinglot/entities"
+)
+
+var ErrInvalidConfig = errors.New("invalid lot configuration")
+
+// LotConfig describes a whole parking lot: its floors and spots, its gates and how it prices stays
+// It is read from JSON by ParseLotConfig, or from YAML or JSON files by the lotconfig package, and built
+// into a service by NewParkingLotServiceFromConfig
+//
+//	floors:
+//	  - name: Level 1
+//	    spots:
+//	      - kind: car
+//	        capacity: 40
+//	        features:
+//	          - {from: 1, to: 4, attributes: [ev-charger], charger_kw: 22}
+//	gates:
+//	  - {id: north-in, direction: entry, floor: 1}
+//	pricing:
+//	  rates: {car: 300, motorcycle: 100}
+//	  schedule:
+//	    version: tariff-2026
+//	    bands:
+//	      car: [{days: [mon, tue, wed, thu, fri], start: "07:00", end: "19:00", rate: 450}]
+type LotConfig struct {
+	Floors     []FloorConfig    `json:"floors"`
+	Gates      []GateConfig     `json:"gates,omitempty"`
+	Pricing    PricingConfig    `json:"pricing,omitempty"`
+	Allocation AllocationConfig `json:"allocation,omitempty"`
+}
+
+// FloorConfig describes one floor; floors are numbered from 1 in the order they are listed
+type FloorConfig struct {
+	Name  string           `json:"name,omitempty"`
+	Spots []SpotKindConfig `json:"spots"`
+}
+
+// SpotKindConfig describes the spots of one kind on a floor
+type SpotKindConfig struct {
+	Kind     string            `json:"kind"` // Vehicle type the spots are built for, e.g. "car"
+	Capacity int               `json:"capacity"`
+	Features []SpotRangeConfig `json:"features,omitempty"`
+}
+
+// SpotRangeConfig gives a run of spots their attributes
+type SpotRangeConfig struct {
+	From       int      `json:"from"`         // First spot ID, counting from 1
+	To         int      `json:"to,omitempty"` // Last spot ID, inclusive; 0 means only From
+	Attributes []string `json:"attributes"`   // e.g. "ev-charger", "covered"
+	ChargerKW  float64  `json:"charger_kw,omitempty"`
+}
+
+// GateConfig describes an entry or exit gate
+type GateConfig struct {
+	ID        string `json:"id"`
+	Direction string `json:"direction"` // "entry" or "exit"
+	Floor     int    `json:"floor,omitempty"`
+}
+
+// PricingConfig sets the hourly rates and the pricing policy for new tickets
+// At most one of Tariff and Schedule may be set; with neither, stays are priced by HourlyPolicy
+type PricingConfig struct {
+	Rates    map[string]int        `json:"rates,omitempty"` // Price per hour in cents by vehicle type; missing types use their default rate
+	Tariff   *TariffConfig         `json:"tariff,omitempty"`
+	Schedule *RateScheduleConfig   `json:"schedule,omitempty"`
+	Dynamic  *DynamicPricingConfig `json:"dynamic,omitempty"`
+}
+
+// TariffConfig configures a TariffPolicy; durations are written like "15m" or "1h30m"
+type TariffConfig struct {
+	Version          string `json:"version"`
+	GracePeriod      string `json:"grace_period,omitempty"`
+	FirstHourPercent int    `json:"first_hour_percent,omitempty"`
+	Increment        string `json:"increment,omitempty"`
+	DailyCapHours    int    `json:"daily_cap_hours,omitempty"`
+}
+
+// RateScheduleConfig configures a RateSchedule; bands are keyed by vehicle type
+type RateScheduleConfig struct {
+	Version      string                      `json:"version"`
+	TimeZone     string                      `json:"time_zone,omitempty"` // IANA name, e.g. "Europe/Berlin"; defaults to UTC
+	Bands        map[string][]TimeBandConfig `json:"bands,omitempty"`
+	HolidayBands map[string][]TimeBandConfig `json:"holiday_bands,omitempty"`
+	Holidays     []string                    `json:"holidays,omitempty"` // Dates as YYYY-MM-DD
+}
+
+// TimeBandConfig configures a TimeBand; Start and End are times of day like "07:00", and End may be "24:00"
+type TimeBandConfig struct {
+	Days  []string `json:"days,omitempty"` // e.g. "mon" or "monday"; empty means every day
+	Start string   `json:"start"`
+	End   string   `json:"end"`
+	Rate  int      `json:"rate"`
+}
+
+// DynamicPricingConfig configures DynamicPricing; rate limits are keyed by vehicle type
+type DynamicPricingConfig struct {
+	Tiers   []OccupancyTierConfig `json:"tiers"`
+	MinRate map[string]int        `json:"min_rate,omitempty"`
+	MaxRate map[string]int        `json:"max_rate,omitempty"`
+}
+
+// OccupancyTierConfig configures an OccupancyTier
+type OccupancyTierConfig struct {
+	MinOccupancy float64 `json:"min_occupancy"`
+	Multiplier   float64 `json:"multiplier"`
+}
+
+// AllocationConfig chooses the SpotAllocationStrategy
+type AllocationConfig struct {
+	Strategy      string `json:"strategy,omitempty"`       // "fill-lowest-floor" (default), "spread-evenly", "nearest-to-entrance" or "random"
+	EntranceFloor int    `json:"entrance_floor,omitempty"` // For nearest-to-entrance
+	Seed          int64  `json:"seed,omitempty"`           // For random
+}
+
+// ParseLotConfig parses a lot configuration written in JSON
+// Unknown fields are rejected so that misspelt settings do not go unnoticed. The configuration is not
+// validated; NewParkingLotServiceFromConfig and Validate do that
+func ParseLotConfig(data []byte) (*LotConfig, error) {
+	var config LotConfig
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&config); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidConfig, err)
+	}
+	if _, err := decoder.Token(); !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("%w: data after the configuration", ErrInvalidConfig)
+	}
+	return &config, nil
+}
+
+// Validate checks the configuration, reporting every problem found rather than only the first
+func (c *LotConfig) Validate() error {
+	_, err := c.plan()
+	return err
+}
+
+// NewParkingLotServiceFromConfig creates a parking lot service from a configuration
+// The configuration is validated first. Options given here are applied after the ones the
+// configuration implies, so they can add to or override it, e.g. WithClock or WithJournal
+func NewParkingLotServiceFromConfig(config *LotConfig, opts ...Option) (*ParkingLotService, error) {
+	if config == nil {
+		return nil, fmt.Errorf("%w: no configuration", ErrInvalidConfig)
+	}
+	plan, err := config.plan()
+	if err != nil {
+		return nil, err
+	}
+
+	pls := NewParkingLotServiceWithCapacities(plan.capacities, plan.pricing, append(plan.opts, opts...)...)
+	for i, name := range plan.names {
+		pls.floors[i].Name = name
+	}
+	for _, feature := range plan.features {
+		spots := pls.floors[feature.floorID-1].Spots[feature.kind]
+		for spotID := feature.from; spotID <= feature.to; spotID++ {
+			if err := spots.SetSpotAttributes(spotID, feature.attributes, feature.chargerKW); err != nil {
+				return nil, fmt.Errorf("floor %d %s spot %d: %w", feature.floorID, feature.kind, spotID, err)
+			}
+		}
+	}
+	return pls, nil
+}
+
+// Options returns the options the configuration implies beyond its floors and rates: its gates,
+// pricing policy, dynamic pricing and allocation strategy
+// A lot restored from a snapshot takes its floors and rates from the snapshot, so these are what it
+// needs to keep running under the same configuration
+func (c *LotConfig) Options() ([]Option, error) {
+	plan, err := c.plan()
+	if err != nil {
+		return nil, err
+	}
+	return plan.opts, nil
+}
+
+// lotPlan is a validated configuration converted to the service's types
+type lotPlan struct {
+	capacities []map[entities.VehicleType]int
+	names      []string
+	features   []spotFeature
+	pricing    map[entities.VehicleType]int // nil when the configuration sets no rates
+	opts       []Option
+}
+
+// spotFeature gives spots from..to of a kind on a floor their attributes
+type spotFeature struct {
+	floorID    int
+	kind       entities.VehicleType
+	from, to   int
+	attributes entities.SpotAttributes
+	chargerKW  float64
+}
+
+// configProblems collects what is wrong with a configuration, each prefixed with its field path
+type configProblems []string
+
+func (p *configProblems) add(path, format string, args ...any) {
+	*p = append(*p, path+": "+fmt.Sprintf(format, args...))
+}
+
+func (p configProblems) err() error {
+	if len(p) == 0 {
+		return nil
+	}
+	return fmt.Errorf("%w:\n  %s", ErrInvalidConfig, strings.Join(p, "\n  "))
+}
+
+// plan validates the configuration and converts it
+func (c *LotConfig) plan() (*lotPlan, error) {
+	var problems configProblems
+	plan := &lotPlan{}
+
+	if len(c.Floors) == 0 {
+		problems.add("floors", "at least one floor is required")
+	}
+	for i, floor := range c.Floors {
+		c.planFloor(plan, &problems, i, floor)
+	}
+
+	gateIDs := make(map[string]bool, len(c.Gates))
+	var gates []Gate
+	for i, gate := range c.Gates {
+		path := fmt.Sprintf("gates[%d]", i)
+		id := strings.TrimSpace(gate.ID)
+		switch {
+		case id == "":
+			problems.add(path+".id", "is required")
+		case gateIDs[id]:
+			problems.add(path+".id", "duplicate gate %q", id)
+		}
+		gateIDs[id] = true
+
+		direction := GateDirection(strings.ToLower(gate.Direction))
+		if direction != GateEntry && direction != GateExit {
+			problems.add(path+".direction", "must be entry or exit, got %q", gate.Direction)
+		}
+		if gate.Floor < 0 || gate.Floor > len(c.Floors) {
+			problems.add(path+".floor", "no floor %d; floors are numbered 1 to %d", gate.Floor, len(c.Floors))
+		}
+		gates = append(gates, Gate{ID: id, Direction: direction, FloorID: gate.Floor})
+	}
+	if len(gates) > 0 {
+		plan.opts = append(plan.opts, WithGates(gates...))
+	}
+
+	c.planPricing(plan, &problems)
+	c.planAllocation(plan, &problems)
+
+	if err := problems.err(); err != nil {
+		return nil, err
+	}
+	return plan, nil
+}
+
+func (c *LotConfig) planFloor(plan *lotPlan, problems *configProblems, i int, floor FloorConfig) {
+	path := fmt.Sprintf("floors[%d]", i)
+	capacities := make(map[entities.VehicleType]int, len(floor.Spots))
+	if len(floor.Spots) == 0 {
+		problems.add(path+".spots", "at least one spot kind is required")
+	}
+
+	for j, spots := range floor.Spots {
+		spotsPath := fmt.Sprintf("%s.spots[%d]", path, j)
+		kind, ok := entities.VehicleTypeByName(spots.Kind)
+		if !ok {
+			problems.add(spotsPath+".kind", "unknown vehicle type %q", spots.Kind)
+			continue
+		}
+		if _, exists := capacities[kind]; exists {
+			problems.add(spotsPath+".kind", "%s spots are listed twice", kind)
+			continue
+		}
+		if spots.Capacity < 0 {
+			problems.add(spotsPath+".capacity", "must not be negative")
+		}
+		capacities[kind] = spots.Capacity
+
+		for k, feature := range spots.Features {
+			featurePath := fmt.Sprintf("%s.features[%d]", spotsPath, k)
+			to := feature.To
+			if to == 0 {
+				to = feature.From
+			}
+			if feature.From < 1 || to < feature.From || to > spots.Capacity {
+				problems.add(featurePath, "spots %d-%d are not within 1-%d", feature.From, to, spots.Capacity)
+			}
+			attributes, err := entities.ParseSpotAttributes(feature.Attributes...)
+			if err != nil {
+				problems.add(featurePath+".attributes", "%v", err)
+			}
+			if feature.ChargerKW < 0 {
+				problems.add(featurePath+".charger_kw", "must not be negative")
+			} else if feature.ChargerKW > 0 && !attributes.Has(entities.EVCharger) {
+				problems.add(featurePath+".charger_kw", "is only meaningful for ev-charger spots")
+			}
+			plan.features = append(plan.features, spotFeature{
+				floorID:    i + 1,
+				kind:       kind,
+				from:       feature.From,
+				to:         to,
+				attributes: attributes,
+				chargerKW:  feature.ChargerKW,
+			})
+		}
+	}
+	plan.capacities = append(plan.capacities, capacities)
+	plan.names = append(plan.names, strings.TrimSpace(floor.Name))
+}
+
+func (c *LotConfig) planPricing(plan *lotPlan, problems *configProblems) {
+	pricing := c.Pricing
+	if len(pricing.Rates) > 0 {
+		plan.pricing = vehicleTypeRates(problems, "pricing.rates", pricing.Rates)
+	}
+
+	if pricing.Tariff != nil && pricing.Schedule != nil {
+		problems.add("pricing", "tariff and schedule are alternatives; set at most one")
+	}
+	if pricing.Tariff != nil {
+		if policy := planTariff(problems, pricing.Tariff); policy != nil {
+			plan.opts = append(plan.opts, WithPricingPolicy(policy))
+		}
+	}
+	if pricing.Schedule != nil {
+		if policy := planSchedule(problems, pricing.Schedule); policy != nil {
+			plan.opts = append(plan.opts, WithPricingPolicy(policy))
+		}
+	}
+
+	if dynamic := pricing.Dynamic; dynamic != nil {
+		dynamicPricing := &DynamicPricing{
+			MinRate: vehicleTypeRates(problems, "pricing.dynamic.min_rate", dynamic.MinRate),
+			MaxRate: vehicleTypeRates(problems, "pricing.dynamic.max_rate", dynamic.MaxRate),
+		}
+		if len(dynamic.Tiers) == 0 {
+			problems.add("pricing.dynamic.tiers", "at least one tier is required")
+		}
+		for i, tier := range dynamic.Tiers {
+			path := fmt.Sprintf("pricing.dynamic.tiers[%d]", i)
+			if tier.MinOccupancy < 0 || tier.MinOccupancy > 1 {
+				problems.add(path+".min_occupancy", "must be between 0 and 1, got %g", tier.MinOccupancy)
+			}
+			if tier.Multiplier <= 0 {
+				problems.add(path+".multiplier", "must be positive, got %g", tier.Multiplier)
+			}
+			dynamicPricing.Tiers = append(dynamicPricing.Tiers, OccupancyTier{MinOccupancy: tier.MinOccupancy, Multiplier: tier.Multiplier})
+		}
+		for _, name := range sortedKeys(dynamic.MinRate) {
+			minRate := dynamic.MinRate[name]
+			if maxRate, ok := dynamic.MaxRate[name]; ok && minRate > maxRate {
+				problems.add("pricing.dynamic", "min_rate %d for %s is above its max_rate %d", minRate, name, maxRate)
+			}
+		}
+		plan.opts = append(plan.opts, WithDynamicPricing(dynamicPricing))
+	}
+}
+
+// vehicleTypeRates converts rates keyed by vehicle type name
+func vehicleTypeRates(problems *configProblems, path string, rates map[string]int) map[entities.VehicleType]int {
+	if rates == nil {
+		return nil
+	}
+	converted := make(map[entities.VehicleType]int, len(rates))
+	for _, name := range sortedKeys(rates) {
+		rate := rates[name]
+		vehicleType, ok := entities.VehicleTypeByName(name)
+		if !ok {
+			problems.add(path, "unknown vehicle type %q", name)
+			continue
+		}
+		if rate < 0 {
+			problems.add(path+"."+name, "must not be negative")
+		}
+		converted[vehicleType] = rate
+	}
+	return converted
+}
+
+// sortedKeys returns a map's keys in order, so that problems are reported in a stable order
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func planTariff(problems *configProblems, tariff *TariffConfig) *TariffPolicy {
+	policy := &TariffPolicy{
+		VersionID:        tariff.Version,
+		FirstHourPercent: tariff.FirstHourPercent,
+		DailyCapHours:    tariff.DailyCapHours,
+	}
+	if tariff.Version == "" {
+		problems.add("pricing.tariff.version", "is required")
+	}
+	policy.GracePeriod = configDuration(problems, "pricing.tariff.grace_period", tariff.GracePeriod)
+	policy.Increment = configDuration(problems, "pricing.tariff.increment", tariff.Increment)
+	if policy.Increment%time.Minute != 0 || policy.Increment > time.Hour || (policy.Increment > 0 && time.Hour%policy.Increment != 0) {
+		problems.add("pricing.tariff.increment", "must be whole minutes dividing an hour evenly, got %s", tariff.Increment)
+	}
+	if tariff.FirstHourPercent < 0 {
+		problems.add("pricing.tariff.first_hour_percent", "must not be negative")
+	}
+	if tariff.DailyCapHours < 0 {
+		problems.add("pricing.tariff.daily_cap_hours", "must not be negative")
+	}
+	return policy
+}
+
+// configDuration parses an optional duration such as "15m"
+func configDuration(problems *configProblems, path, value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+	duration, err := time.ParseDuration(value)
+	if err != nil || duration < 0 {
+		problems.add(path, "invalid duration %q, want e.g. \"15m\"", value)
+		return 0
+	}
+	return duration
+}
+
+func planSchedule(problems *configProblems, schedule *RateScheduleConfig) *RateSchedule {
+	policy := &RateSchedule{VersionID: schedule.Version}
+	if schedule.Version == "" {
+		problems.add("pricing.schedule.version", "is required")
+	}
+	if schedule.TimeZone != "" {
+		location, err := time.LoadLocation(schedule.TimeZone)
+		if err != nil {
+			problems.add("pricing.schedule.time_zone", "unknown time zone %q", schedule.TimeZone)
+		}
+		policy.Location = location
+	}
+	if len(schedule.Bands) == 0 {
+		problems.add("pricing.schedule.bands", "at least one vehicle type needs bands")
+	}
+	policy.Bands = planBands(problems, "pricing.schedule.bands", schedule.Bands, false)
+	policy.HolidayBands = planBands(problems, "pricing.schedule.holiday_bands", schedule.HolidayBands, true)
+
+	for i, date := range schedule.Holidays {
+		holiday, err := time.Parse("2006-01-02", date)
+		if err != nil {
+			problems.add(fmt.Sprintf("pricing.schedule.holidays[%d]", i), "invalid date %q, want YYYY-MM-DD", date)
+			continue
+		}
+		policy.Holidays = append(policy.Holidays, holiday)
+	}
+	if len(schedule.HolidayBands) > 0 && len(schedule.Holidays) == 0 {
+		problems.add("pricing.schedule.holidays", "holiday_bands are set but no holidays are listed")
+	}
+	return policy
+}
+
+// planBands converts time bands keyed by vehicle type name
+// Holiday bands apply on every holiday, so they may not overlap whatever days they list
+func planBands(problems *configProblems, path string, bands map[string][]TimeBandConfig, holiday bool) map[entities.VehicleType][]TimeBand {
+	if bands == nil {
+		return nil
+	}
+	converted := make(map[entities.VehicleType][]TimeBand, len(bands))
+	for _, name := range sortedKeys(bands) {
+		typeBands := bands[name]
+		vehicleType, ok := entities.VehicleTypeByName(name)
+		if !ok {
+			problems.add(path, "unknown vehicle type %q", name)
+			continue
+		}
+		for i, band := range typeBands {
+			bandPath := fmt.Sprintf("%s.%s[%d]", path, name, i)
+			planned := planBand(problems, bandPath, band)
+			for j, earlier := range converted[vehicleType] {
+				// Bands with an inverted range are reported on their own
+				if planned.Start < planned.End && earlier.Start < earlier.End && planned.overlaps(earlier, holiday) {
+					problems.add(bandPath, "overlaps %s.%s[%d]", path, name, j)
+				}
+			}
+			converted[vehicleType] = append(converted[vehicleType], planned)
+		}
+	}
+	return converted
+}
+
+func planBand(problems *configProblems, path string, band TimeBandConfig) TimeBand {
+	converted := TimeBand{Rate: band.Rate}
+	for _, name := range band.Days {
+		day, ok := parseWeekday(name)
+		if !ok {
+			problems.add(path+".days", "unknown day %q", name)
+			continue
+		}
+		converted.Days = append(converted.Days, day)
+	}
+
+	var startOK, endOK bool
+	converted.Start, startOK = parseTimeOfDay(band.Start)
+	if !startOK {
+		problems.add(path+".start", "invalid time %q, want HH:MM", band.Start)
+	}
+	converted.End, endOK = parseTimeOfDay(band.End)
+	if !endOK {
+		problems.add(path+".end", "invalid time %q, want HH:MM", band.End)
+	}
+	if startOK && endOK && converted.Start >= converted.End {
+		problems.add(path, "start %s is not before end %s", band.Start, band.End)
+	}
+	if band.Rate < 0 {
+		problems.add(path+".rate", "must not be negative")
+	}
+	return converted
+}
+
+// parseTimeOfDay parses "HH:MM" into an offset from midnight; "24:00" is the end of the day
+func parseTimeOfDay(value string) (time.Duration, bool) {
+	if value == "24:00" {
+		return 24 * time.Hour, true
+	}
+	t, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, false
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, true
+}
+
+// parseWeekday parses a day name such as "mon" or "Monday"
+func parseWeekday(name string) (time.Weekday, bool) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if len(name) < 3 {
+		return 0, false
+	}
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		full := strings.ToLower(day.String())
+		if strings.HasPrefix(full, name) {
+			return day, true
+		}
+	}
+	return 0, false
+}
+
+func (c *LotConfig) planAllocation(plan *lotPlan, problems *configProblems) {
+	allocation := c.Allocation
+	switch allocation.Strategy {
+	case "", "fill-lowest-floor":
+		// The service's default
+	case "spread-evenly":
+		plan.opts = append(plan.opts, WithAllocationStrategy(NewSpreadEvenlyStrategy()))
+	case "nearest-to-entrance":
+		if allocation.EntranceFloor < 1 || allocation.EntranceFloor > len(c.Floors) {
+			problems.add("allocation.entrance_floor", "no floor %d; floors are numbered 1 to %d", allocation.EntranceFloor, len(c.Floors))
+		}
+		plan.opts = append(plan.opts, WithAllocationStrategy(NewNearestToEntranceStrategy(allocation.EntranceFloor)))
+	case "random":
+		plan.opts = append(plan.opts, WithAllocationStrategy(NewRandomStrategy(allocation.Seed)))
+	default:
+		problems.add("allocation.strategy", "unknown strategy %q, want fill-lowest-floor, spread-evenly, nearest-to-entrance or random", allocation.Strategy)
+	}
+}
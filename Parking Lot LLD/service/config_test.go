@@ -0,0 +1,108 @@
+package service
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"parkinglot/entities"
+)
+
+const testLotConfig = `{
+	"floors": [
+		{"name": "Ground", "spots": [
+			{"kind": "car", "capacity": 4, "features": [{"from": 3, "to": 4, "attributes": ["ev-charger"], "charger_kw": 22}]},
+			{"kind": "motorcycle", "capacity": 2}
+		]},
+		{"name": "Roof", "spots": [{"kind": "truck", "capacity": 1}]}
+	],
+	"gates": [{"id": "north-in", "direction": "entry", "floor": 1}, {"id": "north-out", "direction": "exit"}],
+	"pricing": {
+		"rates": {"car": 300},
+		"tariff": {"version": "tariff-2026", "grace_period": "10m", "increment": "15m"}
+	}
+}`
+
+func TestNewParkingLotServiceFromConfig(t *testing.T) {
+	config, err := ParseLotConfig([]byte(testLotConfig))
+	if err != nil {
+		t.Fatal(err)
+	}
+	clock := entities.NewManualClock(time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC))
+	pls, err := NewParkingLotServiceFromConfig(config, WithClock(clock))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	status := pls.GetParkingLotStatus()
+	if len(status.Floors) != 2 || status.Floors[0].Name != "Ground" || status.Floors[1].Name != "Roof" {
+		t.Fatalf("floors = %+v, want Ground and Roof", status.Floors)
+	}
+	if total := status.Floors[0].Spots[entities.CAR].Total; total != 4 {
+		t.Errorf("ground floor has %d car spots, want 4", total)
+	}
+
+	ticket, err := pls.ParkVehicleWithOptions(entities.NewCar("EV-1"), ParkOptions{
+		Requirements: entities.SpotRequirements{Require: entities.EVCharger, MinChargerKW: 22},
+		EntryGateID:  "north-in",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ticket.SpotID != 3 || ticket.PricePerHour != 300 || ticket.PolicyVersion != "tariff-2026" {
+		t.Errorf("ticket in spot %d at %d per hour under %q, want spot 3 at 300 under tariff-2026",
+			ticket.SpotID, ticket.PricePerHour, ticket.PolicyVersion)
+	}
+
+	_, err = pls.ParkVehicleWithOptions(entities.NewCar("CAR-1"), ParkOptions{EntryGateID: "south-in"})
+	if !errors.Is(err, ErrUnknownGate) {
+		t.Errorf("parking through an undeclared gate: got %v, want ErrUnknownGate", err)
+	}
+}
+
+func TestLotConfigValidateReportsEveryProblem(t *testing.T) {
+	config, err := ParseLotConfig([]byte(`{
+		"floors": [{"spots": [{"kind": "hovercraft", "capacity": 2}, {"kind": "car", "capacity": -1}]}],
+		"gates": [{"id": "in", "direction": "sideways", "floor": 3}],
+		"pricing": {"tariff": {"increment": "7m"}},
+		"allocation": {"strategy": "closest"}
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = config.Validate()
+	if !errors.Is(err, ErrInvalidConfig) {
+		t.Fatalf("Validate = %v, want ErrInvalidConfig", err)
+	}
+	for _, want := range []string{
+		`floors[0].spots[0].kind: unknown vehicle type "hovercraft"`,
+		`floors[0].spots[1].capacity: must not be negative`,
+		`gates[0].direction: must be entry or exit, got "sideways"`,
+		`gates[0].floor: no floor 3`,
+		`pricing.tariff.version: is required`,
+		`pricing.tariff.increment: must be whole minutes dividing an hour evenly`,
+		`allocation.strategy: unknown strategy "closest"`,
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("problems do not include %q:\n%v", want, err)
+		}
+	}
+
+	if _, err := NewParkingLotServiceFromConfig(config); !errors.Is(err, ErrInvalidConfig) {
+		t.Errorf("NewParkingLotServiceFromConfig = %v, want ErrInvalidConfig", err)
+	}
+}
+
+func TestParseLotConfigRejectsMalformedInput(t *testing.T) {
+	for name, data := range map[string]string{
+		"unknown field": `{"floors": [], "flors": []}`,
+		"wrong type":    `{"floors": "three"}`,
+		"trailing data": `{"floors": []} {}`,
+	} {
+		if _, err := ParseLotConfig([]byte(data)); !errors.Is(err, ErrInvalidConfig) {
+			t.Errorf("%s: got %v, want ErrInvalidConfig", name, err)
+		}
+	}
+}
@@ -0,0 +1,152 @@
+package service
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"parkinglot/entities"
+)
+
+// OccupancyTier applies a multiplier to the hourly rate once occupancy reaches MinOccupancy
+type OccupancyTier struct {
+	MinOccupancy float64 // Fraction of spots in use, from 0 to 1
+	Multiplier   float64
+}
+
+// DynamicPricing adjusts the hourly rate offered at entry to how full the lot is
+// Occupancy is measured for the spot kind the vehicle is about to park in, across all floors
+// The highest tier whose MinOccupancy has been reached applies; below every tier the base rate is used
+type DynamicPricing struct {
+	Tiers   []OccupancyTier
+	MinRate map[entities.VehicleType]int // Floor price per hour for each vehicle type; missing means no floor
+	MaxRate map[entities.VehicleType]int // Ceiling price per hour for each vehicle type; missing means no ceiling
+}
+
+// RateChange records a change in the hourly rate offered for a spot kind, for finance audits
+// Rates are those of the spot kind's own vehicle type; other types parking there are offered the same
+// multiplier on their own base rate
+type RateChange struct {
+	Time         time.Time            `json:"time"`
+	SpotType     entities.VehicleType `json:"spot_type"`
+	Occupancy    float64              `json:"occupancy"`
+	Multiplier   float64              `json:"multiplier"`
+	BaseRate     int                  `json:"base_rate"`
+	PreviousRate int                  `json:"previous_rate"`
+	Rate         int                  `json:"rate"`
+}
+
+// multiplier returns the multiplier of the highest tier reached at the given occupancy
+func (dp *DynamicPricing) multiplier(occupancy float64) float64 {
+	tiers := append([]OccupancyTier(nil), dp.Tiers...)
+	sort.Slice(tiers, func(i, j int) bool {
+		return tiers[i].MinOccupancy < tiers[j].MinOccupancy
+	})
+
+	multiplier := 1.0
+	for _, tier := range tiers {
+		if occupancy >= tier.MinOccupancy {
+			multiplier = tier.Multiplier
+		}
+	}
+	return multiplier
+}
+
+// rate applies the multiplier for the occupancy to a base rate and clamps it to the floor and ceiling
+func (dp *DynamicPricing) rate(vehicleType entities.VehicleType, baseRate int, occupancy float64) (int, float64) {
+	multiplier := dp.multiplier(occupancy)
+	rate := int(math.Round(float64(baseRate) * multiplier))
+	if floor, ok := dp.MinRate[vehicleType]; ok && rate < floor {
+		rate = floor
+	}
+	if ceiling, ok := dp.MaxRate[vehicleType]; ok && rate > ceiling {
+		rate = ceiling
+	}
+	return rate, multiplier
+}
+
+// WithDynamicPricing makes the hourly rate offered at entry follow occupancy
+func WithDynamicPricing(dynamic *DynamicPricing) Option {
+	return func(pls *ParkingLotService) {
+		pls.dynamic = dynamic
+	}
+}
+
+// offerRate returns the hourly rate to lock onto a new ticket, logging a change in the spot kind's rate
+// Must be called with the lock held
+func (pls *ParkingLotService) offerRate(vehicleType, spotType entities.VehicleType) (int, float64) {
+	baseRate := pls.rateFor(vehicleType, spotType)
+	if pls.dynamic == nil {
+		return baseRate, 1
+	}
+
+	occupancy := pls.occupancy(spotType)
+	rate, multiplier := pls.dynamic.rate(vehicleType, baseRate, occupancy)
+	pls.logRateChange(spotType, occupancy)
+	return rate, multiplier
+}
+
+// logRateChange records the rate offered for a spot kind at the given occupancy if it differs from the last one
+// The log follows the spot kind rather than the vehicle types parking in it, so that types sharing a kind
+// at different base rates neither log the same change twice nor alternate between their rates
+// Must be called with the lock held
+func (pls *ParkingLotService) logRateChange(spotType entities.VehicleType, occupancy float64) {
+	baseRate := pls.hourlyRate(spotType)
+	rate, multiplier := pls.dynamic.rate(spotType, baseRate, occupancy)
+
+	previous, offered := pls.offeredRates[spotType]
+	if !offered {
+		previous = baseRate
+	}
+	if rate != previous {
+		pls.rateChanges = append(pls.rateChanges, RateChange{
+			Time:         pls.clock.Now(),
+			SpotType:     spotType,
+			Occupancy:    occupancy,
+			Multiplier:   multiplier,
+			BaseRate:     baseRate,
+			PreviousRate: previous,
+			Rate:         rate,
+		})
+	}
+	pls.offeredRates[spotType] = rate
+}
+
+// occupancy returns the fraction of in-service spots of a kind that can't take a vehicle right now,
+// across all floors; spots out of service or blocked don't count either way
+func (pls *ParkingLotService) occupancy(spotType entities.VehicleType) float64 {
+	total, vacant := 0, 0
+	for _, floor := range pls.floors {
+		if spots := floor.GetSpotByVehicleType(spotType); spots != nil {
+			total += spots.GetTotalSpots() - spots.GetStateCount(entities.SpotOutOfService) - spots.GetStateCount(entities.SpotBlocked)
+			vacant += spots.GetVacantCount()
+		}
+	}
+	if total == 0 {
+		return 1
+	}
+	return float64(total-vacant) / float64(total)
+}
+
+// OfferedRate returns the hourly rate a vehicle of the given type would be offered if it parked now
+// in its preferred spot kind
+func (pls *ParkingLotService) OfferedRate(vehicleType entities.VehicleType) int {
+	pls.mu.RLock()
+	defer pls.mu.RUnlock()
+
+	spotType := pls.compatibility.SpotKinds(vehicleType)[0]
+	baseRate := pls.rateFor(vehicleType, spotType)
+	if pls.dynamic == nil {
+		return baseRate
+	}
+	rate, _ := pls.dynamic.rate(vehicleType, baseRate, pls.occupancy(spotType))
+	return rate
+}
+
+// RateChanges returns the log of every change in offered rates, oldest first
+func (pls *ParkingLotService) RateChanges() []RateChange {
+	pls.mu.RLock()
+	defer pls.mu.RUnlock()
+
+	return append([]RateChange(nil), pls.rateChanges...)
+}
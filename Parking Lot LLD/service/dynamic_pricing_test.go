@@ -0,0 +1,41 @@
+package service
+
+import (
+	"testing"
+
+	"parkinglot/entities"
+)
+
+func TestRateChangesFollowSpotKind(t *testing.T) {
+	pricing := map[entities.VehicleType]int{entities.CAR: 20, entities.MOTORCYCLE: 10, entities.TRUCK: 30}
+	pls := NewParkingLotService([][3]int{{4, 0, 0}}, pricing,
+		WithCompatibility(SizeFallbackCompatibility()),
+		WithDynamicPricing(&DynamicPricing{Tiers: []OccupancyTier{{MinOccupancy: 0.5, Multiplier: 2}}}))
+
+	parked := []struct {
+		vehicle  entities.Vehicle
+		wantRate int
+	}{
+		{entities.NewCar("CAR-1"), 20},
+		{entities.NewCar("CAR-2"), 20},
+		{entities.NewMotorCycle("MOTO-1"), 20}, // Falls back to a car spot as the lot reaches half full
+		{entities.NewCar("CAR-3"), 40},
+	}
+	for _, p := range parked {
+		ticket, err := pls.ParkVehicle(p.vehicle)
+		if err != nil {
+			t.Fatalf("park %s: %v", p.vehicle.GetNumberPlate(), err)
+		}
+		if ticket.PricePerHour != p.wantRate {
+			t.Errorf("%s offered %d per hour, want %d", p.vehicle.GetNumberPlate(), ticket.PricePerHour, p.wantRate)
+		}
+	}
+
+	changes := pls.RateChanges()
+	if len(changes) != 1 {
+		t.Fatalf("got %d rate changes, want 1: %+v", len(changes), changes)
+	}
+	if change := changes[0]; change.SpotType != entities.CAR || change.PreviousRate != 20 || change.Rate != 40 {
+		t.Errorf("rate change = %+v, want car spots going from 20 to 40", change)
+	}
+}
@@ -0,0 +1,446 @@
+package service
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"parkinglot/entities"
+)
+
+// EventType names a kind of parking lot event
+type EventType string
+
+const (
+	EventVehicleParked       EventType = "vehicle-parked"
+	EventVehicleExited       EventType = "vehicle-exited"
+	EventSpotReleased        EventType = "spot-released"
+	EventAvailabilityChanged EventType = "availability-changed"
+	EventFloorFull           EventType = "floor-full"
+	EventFloorAvailable      EventType = "floor-available"
+	EventLotFull             EventType = "lot-full"
+	EventLotAvailable        EventType = "lot-available"
+)
+
+// Event is something that happened in the parking lot
+// Subscribers switch on the concrete type to read its details
+//
+// A lot numbers its events in the order they were raised, starting from 1. Operations running
+// concurrently publish after releasing the lot's lock, so their events can reach subscribers out
+// of that order; a subscriber keeping state should ignore events older than the last one applied
+type Event interface {
+	Type() EventType
+	Time() time.Time
+	Sequence() uint64
+}
+
+// VehicleParked is published when a ticket is issued
+type VehicleParked struct {
+	At     time.Time
+	Seq    uint64
+	Ticket entities.Ticket // Copy of the ticket as issued
+}
+
+// VehicleExited is published when a vehicle leaves
+type VehicleExited struct {
+	At     time.Time
+	Seq    uint64
+	Ticket entities.Ticket // Copy of the ticket as closed
+	Fee    int
+}
+
+// SpotReleased is published when spots are freed by a leaving vehicle or an expired reservation
+type SpotReleased struct {
+	At       time.Time
+	Seq      uint64
+	FloorID  int
+	SpotType entities.VehicleType
+	SpotIDs  []int
+}
+
+// AvailabilityChanged is published when the number of vacant spots of a kind on a floor changes
+type AvailabilityChanged struct {
+	At       time.Time
+	Seq      uint64
+	FloorID  int
+	SpotType entities.VehicleType
+	Vacant   int
+	Total    int
+}
+
+// FloorFull is published when the last vacant spot of a kind on a floor is taken
+type FloorFull struct {
+	At       time.Time
+	Seq      uint64
+	FloorID  int
+	SpotType entities.VehicleType
+}
+
+// FloorAvailable is published when a floor that was full for a spot kind has a vacant spot again
+type FloorAvailable struct {
+	At       time.Time
+	Seq      uint64
+	FloorID  int
+	SpotType entities.VehicleType
+}
+
+// LotFull is published when no floor has a vacant spot of a kind
+type LotFull struct {
+	At       time.Time
+	Seq      uint64
+	SpotType entities.VehicleType
+}
+
+// LotAvailable is published when a spot kind that was full across the lot has a vacant spot again
+type LotAvailable struct {
+	At       time.Time
+	Seq      uint64
+	SpotType entities.VehicleType
+}
+
+func (e VehicleParked) Type() EventType        { return EventVehicleParked }
+func (e VehicleParked) Time() time.Time        { return e.At }
+func (e VehicleParked) Sequence() uint64       { return e.Seq }
+func (e VehicleExited) Type() EventType        { return EventVehicleExited }
+func (e VehicleExited) Time() time.Time        { return e.At }
+func (e VehicleExited) Sequence() uint64       { return e.Seq }
+func (e SpotReleased) Type() EventType         { return EventSpotReleased }
+func (e SpotReleased) Time() time.Time         { return e.At }
+func (e SpotReleased) Sequence() uint64        { return e.Seq }
+func (e AvailabilityChanged) Type() EventType  { return EventAvailabilityChanged }
+func (e AvailabilityChanged) Time() time.Time  { return e.At }
+func (e AvailabilityChanged) Sequence() uint64 { return e.Seq }
+func (e FloorFull) Type() EventType            { return EventFloorFull }
+func (e FloorFull) Time() time.Time            { return e.At }
+func (e FloorFull) Sequence() uint64           { return e.Seq }
+func (e FloorAvailable) Type() EventType       { return EventFloorAvailable }
+func (e FloorAvailable) Time() time.Time       { return e.At }
+func (e FloorAvailable) Sequence() uint64      { return e.Seq }
+func (e LotFull) Type() EventType              { return EventLotFull }
+func (e LotFull) Time() time.Time              { return e.At }
+func (e LotFull) Sequence() uint64             { return e.Seq }
+func (e LotAvailable) Type() EventType         { return EventLotAvailable }
+func (e LotAvailable) Time() time.Time         { return e.At }
+func (e LotAvailable) Sequence() uint64        { return e.Seq }
+
+// EventHandler receives published events
+type EventHandler func(Event)
+
+// DeliveryMode decides how events reach a subscriber
+type DeliveryMode int
+
+const (
+	// DeliverSync calls the handler on the publishing goroutine, after the lot's lock is released
+	// The operation that raised the event returns once every synchronous handler has run
+	DeliverSync DeliveryMode = iota
+	// DeliverAsync queues events for the handler on its own goroutine
+	// When the queue is full new events are dropped rather than waiting, so a slow handler never
+	// holds up the lot; Subscription.Dropped reports how many were lost
+	DeliverAsync
+)
+
+// Subscription is a handler registered on an EventBus
+type Subscription struct {
+	handler EventHandler
+	mode    DeliveryMode
+	types   map[EventType]bool // nil means every type
+	queue   chan Event
+	closed  bool
+	dropped atomic.Int64
+	mu      sync.Mutex
+}
+
+// Dropped returns how many events an asynchronous subscription lost to a full queue
+func (s *Subscription) Dropped() int64 {
+	return s.dropped.Load()
+}
+
+// wants reports whether the subscription is interested in an event type
+func (s *Subscription) wants(eventType EventType) bool {
+	return s.types == nil || s.types[eventType]
+}
+
+// deliver hands an event to the subscription without ever blocking on an asynchronous queue
+func (s *Subscription) deliver(event Event) {
+	if s.mode == DeliverSync {
+		s.mu.Lock()
+		closed := s.closed
+		s.mu.Unlock()
+		if !closed {
+			s.handler(event)
+		}
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return
+	}
+	select {
+	case s.queue <- event:
+	default:
+		s.dropped.Add(1)
+	}
+}
+
+// run feeds queued events to an asynchronous handler until the subscription is closed
+func (s *Subscription) run() {
+	for event := range s.queue {
+		s.handler(event)
+	}
+}
+
+// close stops delivery; events already queued for an asynchronous handler are still handled
+func (s *Subscription) close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return
+	}
+	s.closed = true
+	if s.queue != nil {
+		close(s.queue)
+	}
+}
+
+// EventBus fans published events out to subscribers
+type EventBus struct {
+	subscriptions []*Subscription
+	bufferSize    int // queue length of asynchronous subscriptions
+	mu            sync.RWMutex
+}
+
+// NewEventBus creates an EventBus whose asynchronous subscribers queue up to bufferSize events
+func NewEventBus(bufferSize int) *EventBus {
+	if bufferSize < 1 {
+		bufferSize = 1
+	}
+	return &EventBus{bufferSize: bufferSize}
+}
+
+// Subscribe registers a handler for the given event types, or for every type if none are given
+func (b *EventBus) Subscribe(handler EventHandler, mode DeliveryMode, types ...EventType) *Subscription {
+	subscription := &Subscription{handler: handler, mode: mode}
+	if len(types) > 0 {
+		subscription.types = make(map[EventType]bool, len(types))
+		for _, eventType := range types {
+			subscription.types[eventType] = true
+		}
+	}
+	if mode == DeliverAsync {
+		subscription.queue = make(chan Event, b.bufferSize)
+		go subscription.run()
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.subscriptions = append(b.subscriptions, subscription)
+	return subscription
+}
+
+// Unsubscribe stops delivery to a subscription
+// It is safe to call from inside a handler and more than once
+func (b *EventBus) Unsubscribe(subscription *Subscription) {
+	if subscription == nil {
+		return
+	}
+
+	b.mu.Lock()
+	for i, s := range b.subscriptions {
+		if s == subscription {
+			b.subscriptions = append(b.subscriptions[:i], b.subscriptions[i+1:]...)
+			break
+		}
+	}
+	b.mu.Unlock()
+
+	subscription.close()
+}
+
+// Close unsubscribes every subscription
+func (b *EventBus) Close() {
+	b.mu.Lock()
+	subscriptions := b.subscriptions
+	b.subscriptions = nil
+	b.mu.Unlock()
+
+	for _, subscription := range subscriptions {
+		subscription.close()
+	}
+}
+
+// Publish delivers events to every interested subscriber in subscription order
+func (b *EventBus) Publish(events ...Event) {
+	if len(events) == 0 {
+		return
+	}
+
+	b.mu.RLock()
+	subscriptions := append([]*Subscription(nil), b.subscriptions...)
+	b.mu.RUnlock()
+
+	for _, event := range events {
+		for _, subscription := range subscriptions {
+			if subscription.wants(event.Type()) {
+				subscription.deliver(event)
+			}
+		}
+	}
+}
+
+// WithEventBus publishes the lot's events on the given bus, e.g. to share it between services
+// By default each service has its own bus with 64-event queues for asynchronous subscribers
+func WithEventBus(bus *EventBus) Option {
+	return func(pls *ParkingLotService) {
+		if bus != nil {
+			pls.events = bus
+		}
+	}
+}
+
+// Events returns the bus the lot publishes its events on
+func (pls *ParkingLotService) Events() *EventBus {
+	return pls.events
+}
+
+// Subscribe registers a handler for the lot's events; see EventBus.Subscribe
+func (pls *ParkingLotService) Subscribe(handler EventHandler, mode DeliveryMode, types ...EventType) *Subscription {
+	return pls.events.Subscribe(handler, mode, types...)
+}
+
+// Unsubscribe stops delivery to a subscription; see EventBus.Unsubscribe
+func (pls *ParkingLotService) Unsubscribe(subscription *Subscription) {
+	pls.events.Unsubscribe(subscription)
+}
+
+// emit numbers an event and queues it to be published once the lot's lock is released
+// Must be called with the lock held
+func (pls *ParkingLotService) emit(event Event) {
+	pls.eventSeq++
+	pls.pendingEvents = append(pls.pendingEvents, sequenced(event, pls.eventSeq))
+}
+
+// sequenced returns a copy of one of the lot's events carrying its sequence number
+func sequenced(event Event, seq uint64) Event {
+	switch e := event.(type) {
+	case VehicleParked:
+		e.Seq = seq
+		return e
+	case VehicleExited:
+		e.Seq = seq
+		return e
+	case SpotReleased:
+		e.Seq = seq
+		return e
+	case AvailabilityChanged:
+		e.Seq = seq
+		return e
+	case FloorFull:
+		e.Seq = seq
+		return e
+	case FloorAvailable:
+		e.Seq = seq
+		return e
+	case LotFull:
+		e.Seq = seq
+		return e
+	case LotAvailable:
+		e.Seq = seq
+		return e
+	}
+	return event
+}
+
+// publishEvents adds availability events for whatever changed and publishes everything queued
+// Mutating methods defer it before taking the lock, so subscribers never run under the lock:
+//
+//	defer pls.publishEvents()
+//	pls.mu.Lock()
+//	defer pls.mu.Unlock()
+func (pls *ParkingLotService) publishEvents() {
+	pls.mu.Lock()
+	pls.checkAvailability()
+	events := pls.pendingEvents
+	pls.pendingEvents = nil
+	pls.mu.Unlock()
+
+	pls.events.Publish(events...)
+}
+
+// availabilityKey identifies a spot kind on a floor; floor 0 stands for the whole lot
+type availabilityKey struct {
+	floorID  int
+	spotType entities.VehicleType
+}
+
+// resetAvailability takes the current vacant counts as the last ones seen without emitting events,
+// e.g. after the lot was rebuilt from a snapshot or journal
+// Must be called with the lock held
+func (pls *ParkingLotService) resetAvailability() {
+	pls.lastVacant = make(map[availabilityKey]int)
+	pls.checkAvailability()
+	pls.pendingEvents = nil
+}
+
+// checkAvailability compares vacant counts with the last ones seen and emits events for the changes
+// Must be called with the lock held
+func (pls *ParkingLotService) checkAvailability() {
+	now := pls.clock.Now()
+	lotVacant := make(map[entities.VehicleType]int)
+	for _, floor := range pls.floors {
+		for _, spotType := range sortedSpotKinds(floor) {
+			spots := floor.Spots[spotType]
+			vacant := spots.GetVacantCount()
+			lotVacant[spotType] += vacant
+
+			key := availabilityKey{floor.ID, spotType}
+			previous, seen := pls.lastVacant[key]
+			pls.lastVacant[key] = vacant
+			if !seen || previous == vacant {
+				continue
+			}
+
+			pls.emit(AvailabilityChanged{At: now, FloorID: floor.ID, SpotType: spotType, Vacant: vacant, Total: spots.GetTotalSpots()})
+			switch {
+			case vacant == 0:
+				pls.emit(FloorFull{At: now, FloorID: floor.ID, SpotType: spotType})
+			case previous == 0:
+				pls.emit(FloorAvailable{At: now, FloorID: floor.ID, SpotType: spotType})
+			}
+		}
+	}
+
+	for _, info := range entities.VehicleTypes() {
+		vacant, ok := lotVacant[info.Type]
+		if !ok {
+			continue
+		}
+		key := availabilityKey{0, info.Type}
+		previous, seen := pls.lastVacant[key]
+		pls.lastVacant[key] = vacant
+		if !seen || previous == vacant {
+			continue
+		}
+		switch {
+		case vacant == 0:
+			pls.emit(LotFull{At: now, SpotType: info.Type})
+		case previous == 0:
+			pls.emit(LotAvailable{At: now, SpotType: info.Type})
+		}
+	}
+}
+
+// sortedSpotKinds returns the spot kinds configured on a floor in registry order
+func sortedSpotKinds(floor *entities.ParkingSpace) []entities.VehicleType {
+	kinds := make([]entities.VehicleType, 0, len(floor.Spots))
+	for _, info := range entities.VehicleTypes() {
+		if _, ok := floor.Spots[info.Type]; ok {
+			kinds = append(kinds, info.Type)
+		}
+	}
+	return kinds
+}
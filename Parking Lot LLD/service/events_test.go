@@ -0,0 +1,97 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"parkinglot/entities"
+)
+
+func TestEventsNumberedInOrder(t *testing.T) {
+	clock := entities.NewManualClock(time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC))
+	pls := NewParkingLotService([][3]int{{1, 0, 0}}, nil, WithClock(clock))
+	var events []Event
+	pls.Subscribe(func(event Event) { events = append(events, event) }, DeliverSync)
+
+	ticket, err := pls.ParkVehicle(entities.NewCar("EVT-1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := pls.PayTicket(ticket.ID, entities.PaymentCash, 0); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := pls.UnparkVehicle(ticket.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []EventType{
+		EventVehicleParked, EventAvailabilityChanged, EventFloorFull, EventLotFull,
+		EventSpotReleased, EventVehicleExited, EventAvailabilityChanged, EventFloorAvailable, EventLotAvailable,
+	}
+	if len(events) != len(want) {
+		t.Fatalf("got %d events, want %d", len(events), len(want))
+	}
+	for i, event := range events {
+		if event.Type() != want[i] || event.Sequence() != uint64(i+1) {
+			t.Errorf("event %d is %s numbered %d, want %s numbered %d", i, event.Type(), event.Sequence(), want[i], i+1)
+		}
+	}
+	if status := pls.GetParkingLotStatus(); status.EventSeq != uint64(len(want)) {
+		t.Errorf("status taken after event %d, want %d", status.EventSeq, len(want))
+	}
+}
+
+func TestEventBusDeliversSubscribedTypes(t *testing.T) {
+	bus := NewEventBus(4)
+	var parked, all []Event
+	bus.Subscribe(func(event Event) { parked = append(parked, event) }, DeliverSync, EventVehicleParked)
+	var self *Subscription
+	self = bus.Subscribe(func(event Event) {
+		all = append(all, event)
+		bus.Unsubscribe(self) // Unsubscribing from inside a handler must not deadlock
+	}, DeliverSync)
+
+	bus.Publish(VehicleParked{Seq: 1}, LotFull{Seq: 2}, VehicleParked{Seq: 3})
+	if len(parked) != 2 || parked[0].Sequence() != 1 || parked[1].Sequence() != 3 {
+		t.Errorf("VehicleParked subscriber got %v, want events 1 and 3", parked)
+	}
+	if len(all) != 1 {
+		t.Errorf("subscriber that unsubscribed itself got %d events, want 1", len(all))
+	}
+}
+
+func TestEventBusDropsWhenAsyncQueueFull(t *testing.T) {
+	bus := NewEventBus(1)
+	started := make(chan struct{}, 1)
+	release := make(chan struct{})
+	handled := make(chan Event, 3)
+	subscription := bus.Subscribe(func(event Event) {
+		select {
+		case started <- struct{}{}:
+		default:
+		}
+		<-release
+		handled <- event
+	}, DeliverAsync)
+
+	// The handler is busy with the first event, the second fills the queue and the third is dropped
+	bus.Publish(LotFull{Seq: 1})
+	<-started
+	bus.Publish(LotAvailable{Seq: 2}, LotFull{Seq: 3})
+	if dropped := subscription.Dropped(); dropped != 1 {
+		t.Errorf("dropped %d events, want 1", dropped)
+	}
+
+	close(release)
+	bus.Unsubscribe(subscription)
+	for _, want := range []uint64{1, 2} {
+		select {
+		case event := <-handled:
+			if event.Sequence() != want {
+				t.Errorf("handled event %d, want %d", event.Sequence(), want)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("event %d was never handled", want)
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+)
+
+var ErrUnknownGate = errors.New("unknown gate")
+
+// GateDirection says which way a gate lets vehicles through
+type GateDirection string
+
+const (
+	GateEntry GateDirection = "entry"
+	GateExit  GateDirection = "exit"
+)
+
+// Gate describes an entry or exit gate of the lot
+type Gate struct {
+	ID        string
+	Direction GateDirection
+	FloorID   int // Floor the gate is on; 0 when not recorded
+}
+
+// WithGates declares the lot's gates
+// Once a lot has gates, parking and unparking through a gate it does not know fails with
+// ErrUnknownGate; a lot without gates accepts any gate ID
+func WithGates(gates ...Gate) Option {
+	return func(pls *ParkingLotService) {
+		for _, gate := range gates {
+			if pls.gates == nil {
+				pls.gates = make(map[string]Gate)
+			}
+			pls.gates[gate.ID] = gate
+		}
+	}
+}
+
+// Gates returns the lot's declared gates
+func (pls *ParkingLotService) Gates() []Gate {
+	gates := make([]Gate, 0, len(pls.gates))
+	for _, gate := range pls.gates {
+		gates = append(gates, gate)
+	}
+	sort.Slice(gates, func(i, j int) bool {
+		return gates[i].ID < gates[j].ID
+	})
+	return gates
+}
+
+// checkGate verifies that a gate ID names a declared gate of the given direction
+// An empty ID, or any ID in a lot without gates, is accepted
+func (pls *ParkingLotService) checkGate(gateID string, direction GateDirection) error {
+	if gateID == "" || pls.gates == nil {
+		return nil
+	}
+	gate, ok := pls.gates[gateID]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrUnknownGate, gateID)
+	}
+	if gate.Direction != direction {
+		return fmt.Errorf("%w: %s is an %s gate", ErrUnknownGate, gateID, gate.Direction)
+	}
+	return nil
+}
@@ -0,0 +1,324 @@
+package service
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"parkinglot/entities"
+)
+
+var (
+	ErrJournal       = errors.New("journal write failed")
+	ErrJournalClosed = errors.New("journal is closed")
+)
+
+// maxJournalRecord bounds the size of one record; a larger length in a frame header means the
+// header itself is damaged
+const maxJournalRecord = 1 << 20
+
+var journalCRC = crc32.MakeTable(crc32.Castagnoli)
+
+// JournalRecordType names the operation a journal record describes
+type JournalRecordType string
+
+const (
+	JournalPark        JournalRecordType = "park"
+	JournalPay         JournalRecordType = "pay"
+	JournalUnpark      JournalRecordType = "unpark"
+	JournalLostTicket  JournalRecordType = "lost-ticket"
+	JournalReservation JournalRecordType = "reservation"
+	JournalSpotState   JournalRecordType = "spot-state"
+	JournalFloor       JournalRecordType = "floor"
+	JournalAbort       JournalRecordType = "abort"      // The change of an earlier record failed and was undone
+	JournalCheckpoint  JournalRecordType = "checkpoint" // Written when the journal is compacted, carries no operation
+)
+
+// JournalRecord is one operation in the journal
+// Records hold the outcome of an operation rather than its inputs, so replay does not depend on
+// the allocation strategy, the clock or random ticket IDs
+type JournalRecord struct {
+	Seq         uint64               `json:"seq"`
+	Type        JournalRecordType    `json:"type"`
+	Park        *TicketSnapshot      `json:"park,omitempty"`
+	Pay         *PaymentRecord       `json:"pay,omitempty"`
+	Unpark      *ExitRecord          `json:"unpark,omitempty"`
+	LostTicket  *LostTicketRecord    `json:"lost_ticket,omitempty"`
+	Reservation *ReservationSnapshot `json:"reservation,omitempty"` // The reservation as booked, held, moved or closed
+	SpotState   *SpotStateRecord     `json:"spot_state,omitempty"`
+	Floor       *FloorRecord         `json:"floor,omitempty"`
+	Aborts      uint64               `json:"aborts,omitempty"` // Sequence number of the record an abort cancels
+}
+
+// PaymentRecord is a payment attempt and the ticket's paid totals after it
+type PaymentRecord struct {
+	Payment    PaymentSnapshot `json:"payment"`
+	PaidAmount int             `json:"paid_amount"`
+	PaidAt     time.Time       `json:"paid_at"`
+}
+
+// ExitRecord is a vehicle leaving the lot
+type ExitRecord struct {
+	TicketID   string    `json:"ticket_id"`
+	ExitTime   time.Time `json:"exit_time"`
+	Fee        int       `json:"fee"`
+	ExitGateID string    `json:"exit_gate_id,omitempty"`
+}
+
+// LostTicketRecord is a lost ticket being voided and replaced
+type LostTicketRecord struct {
+	Replacement TicketSnapshot `json:"replacement"`
+	VoidedAt    time.Time      `json:"voided_at"`
+	Audit       AuditEntry     `json:"audit"`
+}
+
+// SpotStateRecord is an operator setting the state of spots
+type SpotStateRecord struct {
+	FloorID  int                  `json:"floor_id"`
+	SpotType entities.VehicleType `json:"spot_type"`
+	SpotIDs  []int                `json:"spot_ids"`
+	State    entities.SpotState   `json:"state"`
+	Audit    AuditEntry           `json:"audit"`
+}
+
+// FloorRecord is an operator closing or reopening a floor
+type FloorRecord struct {
+	FloorID int        `json:"floor_id"`
+	Closed  bool       `json:"closed"`
+	Audit   AuditEntry `json:"audit"`
+}
+
+// Journal is an append-only file of parking operations, written ahead of each change to the lot
+// Each record is framed as a 4-byte big-endian length, a 4-byte CRC-32C of the payload and the
+// JSON payload, and is synced to disk before Append returns
+type Journal struct {
+	path      string
+	file      *os.File
+	size      int64           // bytes of valid records in the file
+	seq       uint64          // sequence number of the last record written
+	records   []JournalRecord // records found when the journal was opened
+	truncated int64           // bytes of torn or corrupt tail dropped when the journal was opened
+	mu        sync.Mutex
+}
+
+// OpenJournal opens or creates a journal file and reads the records already in it
+// A torn or corrupt record ends the journal: it and everything after it are truncated, since a
+// crash mid-append can only damage the tail and nothing after it was ever acknowledged
+func OpenJournal(path string) (*Journal, error) {
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	if err := syncDir(filepath.Dir(path)); err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	j := &Journal{path: path, file: file}
+	if err := j.load(); err != nil {
+		file.Close()
+		return nil, fmt.Errorf("open journal %s: %w", path, err)
+	}
+	return j, nil
+}
+
+// load reads every intact record and truncates the file after the last one
+func (j *Journal) load() error {
+	info, err := j.file.Stat()
+	if err != nil {
+		return err
+	}
+
+	reader := bufio.NewReader(j.file)
+	for {
+		record, n, ok := readJournalFrame(reader)
+		if !ok {
+			break
+		}
+		// Sequence numbers only grow; a checkpoint repeats the number of the record before it
+		if record.Seq < j.seq || (record.Seq == j.seq && record.Type != JournalCheckpoint) {
+			break
+		}
+		j.records = append(j.records, record)
+		j.seq = record.Seq
+		j.size += n
+	}
+
+	if j.truncated = info.Size() - j.size; j.truncated > 0 {
+		if err := j.file.Truncate(j.size); err != nil {
+			return err
+		}
+		if err := j.file.Sync(); err != nil {
+			return err
+		}
+	}
+	_, err = j.file.Seek(j.size, io.SeekStart)
+	return err
+}
+
+// readJournalFrame reads one frame, reporting false at the end of the file or at a damaged frame
+func readJournalFrame(reader io.Reader) (JournalRecord, int64, bool) {
+	var header [8]byte
+	if _, err := io.ReadFull(reader, header[:]); err != nil {
+		return JournalRecord{}, 0, false
+	}
+	length := binary.BigEndian.Uint32(header[0:4])
+	if length == 0 || length > maxJournalRecord {
+		return JournalRecord{}, 0, false
+	}
+
+	payload := make([]byte, length)
+	if _, err := io.ReadFull(reader, payload); err != nil {
+		return JournalRecord{}, 0, false
+	}
+	if crc32.Checksum(payload, journalCRC) != binary.BigEndian.Uint32(header[4:8]) {
+		return JournalRecord{}, 0, false
+	}
+
+	var record JournalRecord
+	if err := json.Unmarshal(payload, &record); err != nil {
+		return JournalRecord{}, 0, false
+	}
+	return record, int64(len(header)) + int64(length), true
+}
+
+// Append assigns the record the next sequence number, writes it and syncs it to disk
+// If the write fails the file is cut back to its previous end, so a failed append leaves no torn record
+func (j *Journal) Append(record *JournalRecord) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	if j.file == nil {
+		return ErrJournalClosed
+	}
+
+	record.Seq = j.seq + 1
+	if err := j.write(record); err != nil {
+		return err
+	}
+	j.seq = record.Seq
+	return nil
+}
+
+// write frames a record and syncs it at the end of the file
+// Must be called with the lock held
+func (j *Journal) write(record *JournalRecord) error {
+	payload, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	if len(payload) > maxJournalRecord {
+		return fmt.Errorf("record of %d bytes is too large", len(payload))
+	}
+
+	frame := make([]byte, 8+len(payload))
+	binary.BigEndian.PutUint32(frame[0:4], uint32(len(payload)))
+	binary.BigEndian.PutUint32(frame[4:8], crc32.Checksum(payload, journalCRC))
+	copy(frame[8:], payload)
+
+	if _, err := j.file.Write(frame); err != nil {
+		j.rollback()
+		return err
+	}
+	if err := j.file.Sync(); err != nil {
+		j.rollback()
+		return err
+	}
+	j.size += int64(len(frame))
+	return nil
+}
+
+// rollback cuts off a partly written frame
+// Must be called with the lock held
+func (j *Journal) rollback() {
+	if j.file.Truncate(j.size) == nil {
+		j.file.Seek(j.size, io.SeekStart)
+	}
+}
+
+// Reset empties the journal once its records are covered by a snapshot
+// A checkpoint record keeps the sequence number, so numbering carries on after a restart
+func (j *Journal) Reset() error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	if j.file == nil {
+		return ErrJournalClosed
+	}
+	if err := j.file.Truncate(0); err != nil {
+		return err
+	}
+	if _, err := j.file.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	j.size = 0
+	j.records = nil
+	return j.write(&JournalRecord{Seq: j.seq, Type: JournalCheckpoint})
+}
+
+// Records returns the records found when the journal was opened, oldest first
+func (j *Journal) Records() []JournalRecord {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return append([]JournalRecord(nil), j.records...)
+}
+
+// LastSeq returns the sequence number of the last record written
+func (j *Journal) LastSeq() uint64 {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.seq
+}
+
+// Truncated returns how many bytes of torn or corrupt tail were dropped when the journal was opened
+func (j *Journal) Truncated() int64 {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.truncated
+}
+
+// Path returns the journal's file path
+func (j *Journal) Path() string {
+	return j.path
+}
+
+// Close closes the journal file; later appends fail with ErrJournalClosed
+func (j *Journal) Close() error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	if j.file == nil {
+		return nil
+	}
+	err := j.file.Close()
+	j.file = nil
+	return err
+}
+
+// skipTo moves the sequence number forward so new records sort after those in a snapshot
+func (j *Journal) skipTo(seq uint64) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	if seq > j.seq {
+		j.seq = seq
+	}
+}
+
+// syncDir syncs a directory so that files created or renamed in it survive a crash
+func syncDir(dir string) error {
+	d, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer d.Close()
+	return d.Sync()
+}
@@ -0,0 +1,318 @@
+package service
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"parkinglot/entities"
+)
+
+// newJournaledLot creates a small lot that journals to a fresh file in dir
+func newJournaledLot(t *testing.T, dir string, clock entities.Clock, opts ...Option) (*ParkingLotService, *Journal) {
+	t.Helper()
+	journal, err := OpenJournal(filepath.Join(dir, "lot.journal"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { journal.Close() })
+	opts = append([]Option{WithClock(clock), WithJournal(journal)}, opts...)
+	return NewParkingLotService([][3]int{{2, 2, 1}, {2, 2, 1}}, nil, opts...), journal
+}
+
+// recoverFresh replays a closed journal onto a new lot with the layout newJournaledLot uses
+func recoverFresh(t *testing.T, journal *Journal, clock entities.Clock) *ParkingLotService {
+	t.Helper()
+	reopened, err := OpenJournal(journal.Path())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { reopened.Close() })
+	recovered := NewParkingLotService([][3]int{{2, 2, 1}, {2, 2, 1}}, nil, WithClock(clock), WithJournal(reopened))
+	if _, err := recovered.Recover(); err != nil {
+		t.Fatal(err)
+	}
+	return recovered
+}
+
+// failingRepository is a ticket repository whose saves can be made to fail
+type failingRepository struct {
+	TicketRepository
+	fail bool
+}
+
+func (r *failingRepository) Save(ticket *entities.Ticket) error {
+	if r.fail {
+		return errors.New("disk full")
+	}
+	return r.TicketRepository.Save(ticket)
+}
+
+func mustSnapshot(t *testing.T, pls *ParkingLotService) *Snapshot {
+	t.Helper()
+	snapshot, err := pls.Snapshot()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return snapshot
+}
+
+func TestRecoverReplaysJournalOntoSnapshot(t *testing.T) {
+	dir := t.TempDir()
+	snapshotPath := filepath.Join(dir, "lot.json")
+	clock := entities.NewManualClock(time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC))
+	pls, journal := newJournaledLot(t, dir, clock)
+
+	car, err := pls.ParkVehicle(entities.NewCar("CAR-1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := pls.SaveSnapshot(snapshotPath); err != nil {
+		t.Fatal(err)
+	}
+
+	// Everything after the snapshot exists only in the journal
+	if _, err := pls.ParkVehicle(entities.NewTruck("TRUCK-1")); err != nil {
+		t.Fatal(err)
+	}
+	clock.Advance(90 * time.Minute)
+	if _, err := pls.PayTicket(car.ID, entities.PaymentCard, 0); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := pls.UnparkVehicle(car.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := pls.ReportLostTicket("TRUCK-1", "attendant"); err != nil {
+		t.Fatal(err)
+	}
+	journal.Close()
+
+	reopened, err := OpenJournal(journal.Path())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer reopened.Close()
+	snapshot, err := LoadSnapshot(snapshotPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	recovered, err := RestoreParkingLotService(snapshot, WithClock(clock), WithJournal(reopened))
+	if err != nil {
+		t.Fatal(err)
+	}
+	applied, err := recovered.Recover()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if applied != 4 {
+		t.Errorf("Recover applied %d records, want the 4 written after the snapshot", applied)
+	}
+	if want, got := mustSnapshot(t, pls), mustSnapshot(t, recovered); !reflect.DeepEqual(want, got) {
+		t.Errorf("recovered lot differs from the original\n got: %+v\nwant: %+v", got, want)
+	}
+
+	// New records carry on from the last one replayed
+	if _, err := recovered.ParkVehicle(entities.NewCar("CAR-2")); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := reopened.LastSeq(), journal.LastSeq()+1; got != want {
+		t.Errorf("next record has sequence number %d, want %d", got, want)
+	}
+}
+
+func TestOpenJournalTruncatesTornTail(t *testing.T) {
+	dir := t.TempDir()
+	clock := entities.NewManualClock(time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC))
+	pls, journal := newJournaledLot(t, dir, clock)
+	for _, plate := range []string{"CAR-1", "CAR-2"} {
+		if _, err := pls.ParkVehicle(entities.NewCar(plate)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	journal.Close()
+
+	tests := []struct {
+		name        string
+		damage      func(data []byte) []byte
+		wantRecords int
+	}{
+		{"partial frame header", func(data []byte) []byte { return append(data, 0, 0, 0) }, 2},
+		{"payload cut short", func(data []byte) []byte { return append(data, 0, 0, 0, 40, 1, 2, 3, 4, 5) }, 2},
+		{"corrupt last record", func(data []byte) []byte { data[len(data)-2] ^= 0xff; return data }, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := os.ReadFile(journal.Path())
+			if err != nil {
+				t.Fatal(err)
+			}
+			path := filepath.Join(t.TempDir(), "damaged.journal")
+			damaged := tt.damage(data)
+			if err := os.WriteFile(path, damaged, 0o644); err != nil {
+				t.Fatal(err)
+			}
+
+			opened, err := OpenJournal(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer opened.Close()
+			if got := len(opened.Records()); got != tt.wantRecords {
+				t.Errorf("read %d records, want %d", got, tt.wantRecords)
+			}
+			if opened.Truncated() == 0 {
+				t.Error("no damaged tail reported")
+			}
+			info, err := os.Stat(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if info.Size() != int64(len(damaged))-opened.Truncated() {
+				t.Errorf("file is %d bytes after truncating %d of %d", info.Size(), opened.Truncated(), len(damaged))
+			}
+		})
+	}
+}
+
+func TestCompactEmptiesJournal(t *testing.T) {
+	dir := t.TempDir()
+	snapshotPath := filepath.Join(dir, "lot.json")
+	clock := entities.NewManualClock(time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC))
+	pls, journal := newJournaledLot(t, dir, clock)
+	if _, err := pls.ParkVehicle(entities.NewCar("CAR-1")); err != nil {
+		t.Fatal(err)
+	}
+	if err := pls.Compact(snapshotPath); err != nil {
+		t.Fatal(err)
+	}
+	lastSeq := journal.LastSeq()
+	journal.Close()
+
+	reopened, err := OpenJournal(journal.Path())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer reopened.Close()
+	records := reopened.Records()
+	if len(records) != 1 || records[0].Type != JournalCheckpoint {
+		t.Fatalf("compacted journal holds %+v, want a single checkpoint", records)
+	}
+	if reopened.LastSeq() != lastSeq {
+		t.Errorf("sequence number %d after compaction, want %d", reopened.LastSeq(), lastSeq)
+	}
+
+	snapshot, err := LoadSnapshot(snapshotPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	recovered, err := RestoreParkingLotService(snapshot, WithClock(clock), WithJournal(reopened))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if applied, err := recovered.Recover(); err != nil || applied != 0 {
+		t.Fatalf("Recover after compaction = %d, %v; want nothing to replay", applied, err)
+	}
+	if _, err := recovered.GetActiveTicketByVehicle("CAR-1"); err != nil {
+		t.Errorf("vehicle parked before compaction: %v", err)
+	}
+}
+
+func TestRecoverSkipsAbortedRecords(t *testing.T) {
+	clock := entities.NewManualClock(time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC))
+	repository := &failingRepository{TicketRepository: NewMemoryTicketRepository()}
+	pls, journal := newJournaledLot(t, t.TempDir(), clock, WithTicketRepository(repository))
+
+	car, err := pls.ParkVehicle(entities.NewCar("CAR-1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	clock.Advance(time.Hour)
+	if _, err := pls.PayTicket(car.ID, entities.PaymentCash, 0); err != nil {
+		t.Fatal(err)
+	}
+
+	// Each of these is journaled, then fails to store its ticket and is undone
+	repository.fail = true
+	if _, err := pls.ParkVehicle(entities.NewCar("CAR-2")); err == nil {
+		t.Fatal("parked with a failing repository")
+	}
+	if _, _, err := pls.UnparkVehicle(car.ID); err == nil {
+		t.Fatal("unparked with a failing repository")
+	}
+	if _, err := pls.ReportLostTicket("CAR-1", "attendant"); err == nil {
+		t.Fatal("replaced a lost ticket with a failing repository")
+	}
+	repository.fail = false
+
+	clock.Advance(time.Minute)
+	if _, err := pls.ParkVehicle(entities.NewCar("CAR-3")); err != nil {
+		t.Fatal(err)
+	}
+	journal.Close()
+
+	recovered := recoverFresh(t, journal, clock)
+	if want, got := mustSnapshot(t, pls), mustSnapshot(t, recovered); !reflect.DeepEqual(want, got) {
+		t.Errorf("recovered lot differs from the original\n got: %+v\nwant: %+v", got, want)
+	}
+	if _, err := recovered.GetActiveTicketByVehicle("CAR-2"); !errors.Is(err, ErrVehicleNotParked) {
+		t.Errorf("vehicle whose park was aborted: got %v, want ErrVehicleNotParked", err)
+	}
+	if active, err := recovered.GetActiveTicketByVehicle("CAR-1"); err != nil || active.ID != car.ID {
+		t.Errorf("vehicle whose exit and lost ticket were aborted has ticket %v, %v; want %s", active, err, car.ID)
+	}
+}
+
+func TestRecoverReplaysOperatorChangesAndReservations(t *testing.T) {
+	clock := entities.NewManualClock(time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC))
+	pls, journal := newJournaledLot(t, t.TempDir(), clock)
+	step := func(err error) {
+		t.Helper()
+		if err != nil {
+			t.Fatal(err)
+		}
+		clock.Advance(time.Minute)
+	}
+	reserve := func(plate string) *entities.Reservation {
+		t.Helper()
+		reservation, err := pls.ReserveSpot(ReservationRequest{
+			NumberPlate: plate,
+			VehicleType: entities.CAR,
+			Start:       clock.Now(),
+			End:         clock.Now().Add(2 * time.Hour),
+		})
+		step(err)
+		return reservation
+	}
+
+	step(pls.SetSpotState(1, entities.CAR, []int{1}, entities.SpotOutOfService, "ops"))
+	booked := reserve("BOOKED-1")
+	step(pls.CloseFloor(1, "ops")) // Moves the hold to the second floor
+	_, err := pls.ParkVehicle(entities.NewCar("CAR-1"))
+	step(err)
+	step(pls.ReopenFloor(1, "ops"))
+	_, err = pls.ConfirmArrival(booked.Code)
+	step(err)
+	cancelled := reserve("CANCELLED-1")
+	step(pls.CancelReservation(cancelled.ID))
+	reserve("NO-SHOW-1")
+	clock.Advance(time.Hour)
+	pls.ProcessReservations()
+	_, err = pls.ParkVehicle(entities.NewCar("CAR-2"))
+	step(err)
+	journal.Close()
+
+	recovered := recoverFresh(t, journal, clock)
+	want, got := mustSnapshot(t, pls), mustSnapshot(t, recovered)
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("recovered lot differs from the original\n got: %+v\nwant: %+v", got, want)
+	}
+	if cars := recovered.GetParkingLotStatus().Floors[0].Spots[entities.CAR]; cars.OutOfService != 1 || cars.Occupied != 1 {
+		t.Errorf("recovered first floor cars %+v, want one out of service and one occupied", cars)
+	}
+	if len(got.AuditLog) != 3 {
+		t.Errorf("recovered %d audit entries, want the spot change, closure and reopening", len(got.AuditLog))
+	}
+}
@@ -0,0 +1,112 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"parkinglot/entities"
+)
+
+var (
+	ErrTicketVoided     = errors.New("ticket has been voided")
+	ErrOperatorRequired = errors.New("operator is required")
+)
+
+// LostTicketPolicy decides the penalty for a lost ticket
+// With UseDailyMaximum the penalty is one day at the most the pricing policy charges per day,
+// or 24 hours at the ticket's hourly rate when the policy has no daily cap; otherwise it is Fee
+type LostTicketPolicy struct {
+	Fee             int // Fixed penalty in cents
+	UseDailyMaximum bool
+}
+
+// dailyCapped is implemented by pricing policies that cap what a day can cost
+type dailyCapped interface {
+	DailyMaximum(pricePerHour int) int
+}
+
+// WithLostTicketPolicy sets the penalty applied to lost tickets
+// Defaults to the daily maximum
+func WithLostTicketPolicy(policy LostTicketPolicy) Option {
+	return func(pls *ParkingLotService) {
+		pls.lostTicket = policy
+	}
+}
+
+// ReportLostTicket handles a driver who lost their ticket
+// The vehicle's session is found by number plate, the lost ticket is voided and a replacement
+// for the same stay is issued with the lost-ticket penalty added to its price
+// What was paid on the lost ticket counts toward the replacement; the attempts stay in its history
+// The operator handling it is recorded in the audit log
+func (pls *ParkingLotService) ReportLostTicket(numberPlate, operator string) (*entities.Ticket, error) {
+	if operator == "" {
+		return nil, ErrOperatorRequired
+	}
+
+	pls.mu.Lock()
+	defer pls.mu.Unlock()
+
+	lost, err := pls.tickets.GetActiveByPlate(numberPlate)
+	if err != nil {
+		return nil, err
+	}
+
+	now := pls.clock.Now()
+	penalty := pls.lostTicketPenalty(lost)
+	replacement := lost.Replace(now)
+	replacement.Penalty += penalty
+	if penalty > 0 {
+		replacement.PaidAt = time.Time{} // The penalty is still due, so the stay is no longer paid in full
+	}
+
+	entry := AuditEntry{
+		Time:        now,
+		Operator:    operator,
+		Action:      AuditLostTicket,
+		TicketID:    lost.ID,
+		NumberPlate: numberPlate,
+		Details:     fmt.Sprintf("voided, replaced by %s with penalty %d", replacement.ID, penalty),
+	}
+	record := LostTicketRecord{Replacement: ticketSnapshot(replacement), VoidedAt: now, Audit: entry}
+	seq, err := pls.writeAhead(JournalRecord{Type: JournalLostTicket, LostTicket: &record})
+	if err != nil {
+		lost.VoidedAt, lost.ReplacedByID = time.Time{}, ""
+		return nil, err
+	}
+
+	if err := pls.storeReplacement(lost, replacement); err != nil {
+		return nil, pls.abortJournaled(seq, err)
+	}
+	pls.audit(entry)
+
+	return replacement.Copy(), nil
+}
+
+// storeReplacement saves a voided ticket and its replacement, restoring the voided one if that fails
+// Must be called with the lock held
+func (pls *ParkingLotService) storeReplacement(lost, replacement *entities.Ticket) error {
+	err := pls.tickets.Save(lost)
+	if err == nil {
+		err = pls.tickets.Save(replacement)
+	}
+	if err != nil {
+		lost.VoidedAt, lost.ReplacedByID = time.Time{}, ""
+		pls.tickets.Save(lost)
+		return fmt.Errorf("failed to store ticket: %w", err)
+	}
+	return nil
+}
+
+// lostTicketPenalty returns the penalty for losing a ticket under the configured policy
+func (pls *ParkingLotService) lostTicketPenalty(ticket *entities.Ticket) int {
+	if !pls.lostTicket.UseDailyMaximum {
+		return pls.lostTicket.Fee
+	}
+	if capped, ok := pls.policies[ticket.PolicyVersion].(dailyCapped); ok {
+		if maximum := capped.DailyMaximum(ticket.PricePerHour); maximum > 0 {
+			return maximum
+		}
+	}
+	return 24 * ticket.PricePerHour
+}
@@ -0,0 +1,138 @@
+package service
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"parkinglot/entities"
+)
+
+func TestLostTicketPenalty(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    []Option
+		penalty int
+	}{
+		{"uncapped policy charges a full day", nil, 24 * 20},
+		{"daily cap of the pricing policy", []Option{WithPricingPolicy(&TariffPolicy{VersionID: "capped", DailyCapHours: 8})}, 8 * 20},
+		{"fixed fee", []Option{WithLostTicketPolicy(LostTicketPolicy{Fee: 150})}, 150},
+		{"fixed fee ignores the daily cap", []Option{
+			WithPricingPolicy(&TariffPolicy{VersionID: "capped", DailyCapHours: 8}),
+			WithLostTicketPolicy(LostTicketPolicy{Fee: 150}),
+		}, 150},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clock := entities.NewManualClock(time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC))
+			pls := NewParkingLotService([][3]int{{1, 0, 0}}, nil, append([]Option{WithClock(clock)}, tt.opts...)...)
+			if _, err := pls.ParkVehicle(entities.NewCar("LOST-1")); err != nil {
+				t.Fatal(err)
+			}
+			clock.Advance(time.Hour)
+
+			replacement, err := pls.ReportLostTicket("LOST-1", "alice")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if replacement.Penalty != tt.penalty {
+				t.Errorf("penalty = %d, want %d", replacement.Penalty, tt.penalty)
+			}
+			due, err := pls.AmountDue(replacement.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if due != 20+tt.penalty {
+				t.Errorf("due after an hour = %d, want the hour plus the penalty, %d", due, 20+tt.penalty)
+			}
+		})
+	}
+}
+
+func TestLostTicketVoidsOriginal(t *testing.T) {
+	clock := entities.NewManualClock(time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC))
+	pls := NewParkingLotService([][3]int{{1, 0, 0}}, nil, WithClock(clock))
+	lost, err := pls.ParkVehicle(entities.NewCar("LOST-1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	clock.Advance(time.Hour)
+
+	if _, err := pls.ReportLostTicket("LOST-1", ""); !errors.Is(err, ErrOperatorRequired) {
+		t.Fatalf("reporting without an operator: got %v, want ErrOperatorRequired", err)
+	}
+	replacement, err := pls.ReportLostTicket("LOST-1", "alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if replacement.ReplacesID != lost.ID || !replacement.EntryTime.Equal(lost.EntryTime) {
+		t.Errorf("replacement replaces %q from %v, want %q from %v",
+			replacement.ReplacesID, replacement.EntryTime, lost.ID, lost.EntryTime)
+	}
+
+	if _, _, err := pls.UnparkVehicle(lost.ID); !errors.Is(err, ErrTicketVoided) || !strings.Contains(err.Error(), replacement.ID) {
+		t.Errorf("unparking with the lost ticket: got %v, want ErrTicketVoided naming %s", err, replacement.ID)
+	}
+	if _, err := pls.PayTicket(lost.ID, entities.PaymentCash, 0); !errors.Is(err, ErrTicketVoided) {
+		t.Errorf("paying the lost ticket: got %v, want ErrTicketVoided", err)
+	}
+	if active, err := pls.GetActiveTicketByVehicle("LOST-1"); err != nil || active.ID != replacement.ID {
+		t.Errorf("active ticket for LOST-1 is %v, %v; want the replacement", active, err)
+	}
+
+	log := pls.AuditLog()
+	if len(log) != 1 {
+		t.Fatalf("audit log has %d entries, want 1", len(log))
+	}
+	if entry := log[0]; entry.Operator != "alice" || entry.Action != AuditLostTicket || entry.TicketID != lost.ID || !strings.Contains(entry.Details, replacement.ID) {
+		t.Errorf("audit entry %+v, want alice voiding %s for %s", entry, lost.ID, replacement.ID)
+	}
+}
+
+func TestLostTicketKeepsPayments(t *testing.T) {
+	clock := entities.NewManualClock(time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC))
+	pls := NewParkingLotService([][3]int{{1, 0, 0}}, nil, WithClock(clock),
+		WithLostTicketPolicy(LostTicketPolicy{Fee: 150}))
+	lost, err := pls.ParkVehicle(entities.NewCar("LOST-1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	clock.Advance(90 * time.Minute)
+	if _, err := pls.PayTicket(lost.ID, entities.PaymentCash, 0); err != nil {
+		t.Fatal(err)
+	}
+
+	replacement, err := pls.ReportLostTicket("LOST-1", "alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// What was paid counts toward the replacement, but the attempts stay in the lost ticket's history
+	if replacement.PaidAmount != 40 {
+		t.Errorf("replacement has %d paid, want the 40 paid on the lost ticket", replacement.PaidAmount)
+	}
+	if due, _ := pls.AmountDue(replacement.ID); due != 150 {
+		t.Errorf("due on the replacement = %d, want only the penalty, 150", due)
+	}
+	if payments, _ := pls.GetPayments(lost.ID); len(payments) != 1 {
+		t.Errorf("lost ticket has %d payments, want 1", len(payments))
+	}
+	if payments, _ := pls.GetPayments(replacement.ID); len(payments) != 0 {
+		t.Errorf("replacement has %d payments, want none yet", len(payments))
+	}
+	if _, _, err := pls.UnparkVehicle(replacement.ID); !errors.Is(err, ErrPaymentRequired) {
+		t.Errorf("exit with the penalty unpaid: got %v, want ErrPaymentRequired", err)
+	}
+
+	if _, err := pls.PayTicket(replacement.ID, entities.PaymentCash, 0); err != nil {
+		t.Fatal(err)
+	}
+	_, fee, err := pls.UnparkVehicle(replacement.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fee != 40+150 {
+		t.Errorf("fee = %d, want the stay plus the penalty, 190", fee)
+	}
+}
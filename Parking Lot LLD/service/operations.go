@@ -0,0 +1,173 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+
+	"parkinglot/entities"
+)
+
+// SetSpotState takes spots out of service, blocks them or returns them to service
+// state must be Available, OutOfService or Blocked; occupied and reserved spots can't be changed
+// The change is recorded in the audit log under the operator's name
+func (pls *ParkingLotService) SetSpotState(floorID int, spotType entities.VehicleType, spotIDs []int, state entities.SpotState, operator string) error {
+	if operator == "" {
+		return ErrOperatorRequired
+	}
+
+	defer pls.publishEvents()
+	pls.mu.Lock()
+	defer pls.mu.Unlock()
+
+	if _, err := pls.spotsOn(floorID, spotType); err != nil {
+		return err
+	}
+
+	change := SpotStateRecord{
+		FloorID:  floorID,
+		SpotType: spotType,
+		SpotIDs:  spotIDs,
+		State:    state,
+		Audit: AuditEntry{
+			Time:     pls.clock.Now(),
+			Operator: operator,
+			Action:   AuditSpotState,
+			Details:  fmt.Sprintf("floor %d %s spots %v set to %s", floorID, spotType, spotIDs, state),
+		},
+	}
+	seq, err := pls.writeAhead(JournalRecord{Type: JournalSpotState, SpotState: &change})
+	if err != nil {
+		return err
+	}
+	if err := pls.setSpotState(change); err != nil {
+		return pls.abortJournaled(seq, err)
+	}
+	return nil
+}
+
+// setSpotState applies an operator's change of spot state and audits it
+// Must be called with the lock held
+func (pls *ParkingLotService) setSpotState(change SpotStateRecord) error {
+	spots, err := pls.spotsOn(change.FloorID, change.SpotType)
+	if err != nil {
+		return err
+	}
+	if err := spots.SetSpotsState(change.SpotIDs, change.State); err != nil {
+		return err
+	}
+	pls.audit(change.Audit)
+	return nil
+}
+
+// spotsOn returns a floor's spots of one kind
+// Must be called with the lock held
+func (pls *ParkingLotService) spotsOn(floorID int, spotType entities.VehicleType) (entities.ParkingSpot, error) {
+	if floorID < 1 || floorID > len(pls.floors) {
+		return nil, ErrInvalidFloor
+	}
+	spots := pls.floors[floorID-1].GetSpotByVehicleType(spotType)
+	if spots == nil {
+		return nil, fmt.Errorf("floor %d has no %s spots: %w", floorID, spotType, entities.ErrSpotNotFound)
+	}
+	return spots, nil
+}
+
+// CloseFloor takes a whole floor out of service
+// Vacant spots go out of service at once and occupied ones as their vehicles leave; reservations
+// holding spots on the floor are moved to other floors where possible
+func (pls *ParkingLotService) CloseFloor(floorID int, operator string) error {
+	if operator == "" {
+		return ErrOperatorRequired
+	}
+
+	defer pls.publishEvents()
+	pls.mu.Lock()
+	defer pls.mu.Unlock()
+
+	if floorID < 1 || floorID > len(pls.floors) {
+		return ErrInvalidFloor
+	}
+	if pls.floors[floorID-1].Closed {
+		return nil
+	}
+
+	change := FloorRecord{
+		FloorID: floorID,
+		Closed:  true,
+		Audit: AuditEntry{
+			Time:     pls.clock.Now(),
+			Operator: operator,
+			Action:   AuditFloorClosed,
+			Details:  fmt.Sprintf("floor %d closed", floorID),
+		},
+	}
+	if _, err := pls.writeAhead(JournalRecord{Type: JournalFloor, Floor: &change}); err != nil {
+		return err
+	}
+	if err := pls.setFloorClosed(change); err != nil {
+		return err
+	}
+
+	// Held spots on the floor go out of service with it; look for new ones, or wait booked
+	// for the next sweep to find some
+	var errs []error
+	for _, reservation := range pls.openReservations() {
+		if reservation.FloorID != floorID || reservation.Status != entities.ReservationHolding {
+			continue
+		}
+		moved := *reservation
+		if floor, spotType, ids, err := pls.findReservableSpots(reservation); err == nil {
+			moved.FloorID, moved.SpotType, moved.SpotIDs = floor.ID, spotType, ids
+		} else {
+			moved.Status = entities.ReservationBooked
+		}
+		if err := pls.updateReservation(&moved); err != nil {
+			errs = append(errs, fmt.Errorf("move reservation %s: %w", reservation.ID, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// ReopenFloor returns a closed floor to service
+// Spots taken out of service individually by an operator stay out of service
+func (pls *ParkingLotService) ReopenFloor(floorID int, operator string) error {
+	if operator == "" {
+		return ErrOperatorRequired
+	}
+
+	defer pls.publishEvents()
+	pls.mu.Lock()
+	defer pls.mu.Unlock()
+
+	if floorID < 1 || floorID > len(pls.floors) {
+		return ErrInvalidFloor
+	}
+	if !pls.floors[floorID-1].Closed {
+		return nil
+	}
+
+	change := FloorRecord{
+		FloorID: floorID,
+		Audit: AuditEntry{
+			Time:     pls.clock.Now(),
+			Operator: operator,
+			Action:   AuditFloorReopened,
+			Details:  fmt.Sprintf("floor %d reopened", floorID),
+		},
+	}
+	if _, err := pls.writeAhead(JournalRecord{Type: JournalFloor, Floor: &change}); err != nil {
+		return err
+	}
+	return pls.setFloorClosed(change)
+}
+
+// setFloorClosed applies an operator closing or reopening a floor and audits it
+// Must be called with the lock held
+func (pls *ParkingLotService) setFloorClosed(change FloorRecord) error {
+	if change.FloorID < 1 || change.FloorID > len(pls.floors) {
+		return ErrInvalidFloor
+	}
+	pls.floors[change.FloorID-1].SetClosed(change.Closed)
+	pls.audit(change.Audit)
+	return nil
+}
@@ -4,168 +4,463 @@ import (
 	"errors"
 	"fmt"
 	"sync"
+	"time"
 
-	"../entities"
+	"parkinglot/entities"
 )
 
 var (
-	ErrParkingLotFull      = errors.New("parking lot is full")
-	ErrInvalidVehicle      = errors.New("invalid vehicle")
-	ErrTicketNotFound      = errors.New("ticket not found")
-	ErrVehicleNotParked    = errors.New("vehicle is not currently parked")
+	ErrParkingLotFull       = errors.New("parking lot is full")
+	ErrInvalidVehicle       = errors.New("invalid vehicle")
+	ErrTicketNotFound       = errors.New("ticket not found")
+	ErrVehicleNotParked     = errors.New("vehicle is not currently parked")
 	ErrInvalidFloor         = errors.New("invalid floor number")
+	ErrVehicleAlreadyParked = errors.New("vehicle is already parked")
 )
 
 // ParkingLotService manages the entire parking lot operations
 // This is the main service layer that coordinates between floors, spots, and tickets
 type ParkingLotService struct {
-	floors          []*entities.ParkingSpace
-	tickets         map[string]*entities.Ticket // ticketID -> ticket
-	activeTickets   map[string]*entities.Ticket // vehicle number plate -> ticket (for quick lookup)
-	pricing         map[entities.VehicleType]int // price per hour for each vehicle type
-	mu              sync.RWMutex
+	floors           []*entities.ParkingSpace
+	tickets          TicketRepository             // every ticket issued, active or not
+	pricing          map[entities.VehicleType]int // price per hour for each vehicle type
+	strategy         SpotAllocationStrategy       // decides which floor and spot a vehicle gets
+	compatibility    CompatibilityRules           // spot kinds each vehicle type may use
+	fallbackPricing  FallbackPricing              // which rate applies to fallback placements
+	policy           PricingPolicy                // pricing policy for new tickets
+	policies         map[string]PricingPolicy     // every known policy by version, for pricing older tickets
+	dynamic          *DynamicPricing              // occupancy-driven rate adjustment (nil when disabled)
+	offeredRates     map[entities.VehicleType]int // last hourly rate offered per spot kind
+	rateChanges      []RateChange                 // audit log of offered rate changes
+	clock            entities.Clock               // source of time for tickets and pricing
+	processors       map[entities.PaymentMethod]PaymentProcessor
+	payments         map[string][]*entities.Payment   // ticketID -> payment attempts, oldest first
+	exitGrace        time.Duration                    // time allowed to leave after paying in full
+	lostTicket       LostTicketPolicy                 // penalty for lost tickets
+	auditLog         []AuditEntry                     // operator actions, oldest first
+	reservations     map[string]*entities.Reservation // reservationID -> reservation
+	reservationGrace time.Duration                    // how long a held spot waits for a late arrival
+	events           *EventBus                        // lifecycle events for subscribers
+	pendingEvents    []Event                          // raised under the lock, published after it is released
+	eventSeq         uint64                           // sequence number of the last event raised
+	lastVacant       map[availabilityKey]int          // vacant spots last reported per floor and kind
+	journal          *Journal                         // write-ahead log of parking operations (nil when disabled)
+	journalSeq       uint64                           // sequence number of the last journal record reflected in the state
+	gates            map[string]Gate                  // declared gates by ID (nil accepts any gate)
+	mu               sync.RWMutex
+}
+
+// Option configures optional behavior of the ParkingLotService
+type Option func(*ParkingLotService)
+
+// WithClock sets the clock used for entry and exit times, pricing and audit records
+// Defaults to the system clock
+func WithClock(clock entities.Clock) Option {
+	return func(pls *ParkingLotService) {
+		if clock != nil {
+			pls.clock = clock
+		}
+	}
+}
+
+// Now returns the current time according to the service's clock
+func (pls *ParkingLotService) Now() time.Time {
+	return pls.clock.Now()
+}
+
+// WithAllocationStrategy sets the strategy used by ParkVehicle to choose a spot
+// Defaults to FillLowestFloorStrategy
+func WithAllocationStrategy(strategy SpotAllocationStrategy) Option {
+	return func(pls *ParkingLotService) {
+		if strategy != nil {
+			pls.strategy = strategy
+		}
+	}
 }
 
 // NewParkingLotService creates a new parking lot service
 // floorsConfig: array of floor configurations, each containing [carCapacity, motorcycleCapacity, truckCapacity]
-func NewParkingLotService(floorsConfig [][3]int, pricing map[entities.VehicleType]int) *ParkingLotService {
-	floors := make([]*entities.ParkingSpace, len(floorsConfig))
+func NewParkingLotService(floorsConfig [][3]int, pricing map[entities.VehicleType]int, opts ...Option) *ParkingLotService {
+	capacities := make([]map[entities.VehicleType]int, len(floorsConfig))
 	for i, config := range floorsConfig {
-		floors[i] = entities.NewParkingSpace(i+1, config[0], config[1], config[2])
+		capacities[i] = map[entities.VehicleType]int{
+			entities.CAR:        config[0],
+			entities.MOTORCYCLE: config[1],
+			entities.TRUCK:      config[2],
+		}
+	}
+	return NewParkingLotServiceWithCapacities(capacities, pricing, opts...)
+}
+
+// NewParkingLotServiceWithCapacities creates a parking lot service for any set of registered spot kinds
+// capacities: one map per floor, from spot kind to the number of spots of that kind
+// pricing: price per hour for each vehicle type; types missing from it use their registered default rate
+func NewParkingLotServiceWithCapacities(capacities []map[entities.VehicleType]int, pricing map[entities.VehicleType]int, opts ...Option) *ParkingLotService {
+	floors := make([]*entities.ParkingSpace, len(capacities))
+	for i, floorCapacities := range capacities {
+		floors[i] = entities.NewParkingSpace(i+1, floorCapacities)
 	}
 
 	// Default pricing if not provided
 	if pricing == nil {
-		pricing = map[entities.VehicleType]int{
-			entities.MOTORCYCLE: 10, // $0.10 per hour
-			entities.CAR:        20, // $0.20 per hour
-			entities.TRUCK:      50, // $0.50 per hour
+		pricing = make(map[entities.VehicleType]int)
+		for _, info := range entities.VehicleTypes() {
+			pricing[info.Type] = info.DefaultRate
 		}
 	}
 
-	return &ParkingLotService{
-		floors:        floors,
-		tickets:       make(map[string]*entities.Ticket),
-		activeTickets: make(map[string]*entities.Ticket),
-		pricing:       pricing,
+	pls := &ParkingLotService{
+		floors:           floors,
+		tickets:          NewMemoryTicketRepository(),
+		pricing:          pricing,
+		strategy:         NewFillLowestFloorStrategy(),
+		compatibility:    DefaultCompatibility(),
+		policy:           NewHourlyPolicy(),
+		policies:         make(map[string]PricingPolicy),
+		offeredRates:     make(map[entities.VehicleType]int),
+		clock:            entities.NewRealClock(),
+		processors:       defaultPaymentProcessors(),
+		payments:         make(map[string][]*entities.Payment),
+		exitGrace:        15 * time.Minute,
+		lostTicket:       LostTicketPolicy{UseDailyMaximum: true},
+		reservations:     make(map[string]*entities.Reservation),
+		reservationGrace: 15 * time.Minute,
+		events:           NewEventBus(64),
+		lastVacant:       make(map[availabilityKey]int),
 	}
+	pls.policies[pls.policy.Version()] = pls.policy
+	for _, opt := range opts {
+		opt(pls)
+	}
+	pls.checkAvailability() // Record starting vacancy so the first change can be reported
+	return pls
+}
+
+// ParkOptions carries per-request parking preferences
+type ParkOptions struct {
+	// Requirements are merged with the ones registered for the vehicle's type
+	Requirements entities.SpotRequirements
+	// EntryGateID records the gate the vehicle came in through on its ticket
+	EntryGateID string
+}
+
+// UnparkOptions carries per-request exit details
+type UnparkOptions struct {
+	// ExitGateID records the gate the vehicle leaves through on its ticket
+	ExitGateID string
 }
 
 // ParkVehicle parks a vehicle and returns a ticket
-// Strategy: Find nearest available spot (check floors from bottom to top)
+// Tickets returned by the service are copies; later changes to the stay are read with GetTicket
+// The spot is chosen by the configured SpotAllocationStrategy, trying the vehicle's own spot kind
+// first and then each fallback kind allowed by the compatibility rules
 func (pls *ParkingLotService) ParkVehicle(vehicle entities.Vehicle) (*entities.Ticket, error) {
+	return pls.ParkVehicleWithOptions(vehicle, ParkOptions{})
+}
+
+// ParkVehicleWithOptions parks a vehicle honoring the spot attributes it requires or prefers
+// Preferred attributes are tried across every compatible spot kind before settling for required ones
+func (pls *ParkingLotService) ParkVehicleWithOptions(vehicle entities.Vehicle, opts ParkOptions) (*entities.Ticket, error) {
 	if vehicle == nil {
 		return nil, ErrInvalidVehicle
 	}
+	if err := pls.checkGate(opts.EntryGateID, GateEntry); err != nil {
+		return nil, err
+	}
 
+	defer pls.publishEvents()
 	pls.mu.Lock()
 	defer pls.mu.Unlock()
 
+	// Hold spots for reservations whose window has started so walk-ins skip them
+	pls.sweepReservations()
+
 	// Check if vehicle is already parked
-	if _, exists := pls.activeTickets[vehicle.GetNumberPlate()]; exists {
-		return nil, fmt.Errorf("vehicle %s is already parked", vehicle.GetNumberPlate())
+	if err := pls.checkNotParked(vehicle.GetNumberPlate()); err != nil {
+		return nil, err
 	}
 
 	vehicleType := vehicle.Type()
+	requirements := opts.Requirements
+	if info, ok := entities.LookupVehicleType(vehicleType); ok {
+		requirements = info.Requirements.Merge(requirements)
+	}
 
-	// Find first available spot across all floors
-	for _, floor := range pls.floors {
-		spotCollection := floor.GetSpotByVehicleType(vehicleType)
-		if spotCollection == nil {
-			continue
-		}
+	floor, spotType, ids, err := pls.allocate(vehicle, requirements)
+	if err != nil {
+		return nil, err
+	}
 
-		spotID, err := spotCollection.FindVacantSpot()
-		if err != nil {
-			continue // No spot on this floor, try next
-		}
+	return pls.issueTicket(vehicle, floor, spotType, ids, opts.EntryGateID, nil)
+}
+
+// issueTicket journals and records a ticket for the vehicle in the chosen spots
+// A reservation the ticket fulfills has its held spots handed over to the vehicle
+// Must be called with the lock held
+func (pls *ParkingLotService) issueTicket(vehicle entities.Vehicle, floor *entities.ParkingSpace, spotType entities.VehicleType, ids []int, entryGateID string, reservation *entities.Reservation) (*entities.Ticket, error) {
+	// Lock in the rate offered at entry, based on occupancy before this vehicle parks
+	rate, multiplier := pls.offerRate(vehicle.Type(), spotType)
+
+	// Create ticket
+	ticket := entities.NewTicket(vehicle, floor.ID, spotType, ids, rate, pls.clock.Now())
+	ticket.RateMultiplier = multiplier
+	ticket.PolicyVersion = pls.policy.Version()
+	ticket.EntryGateID = entryGateID
+	if reservation != nil {
+		ticket.ReservationID = reservation.ID
+	}
+
+	saved := ticketSnapshot(ticket)
+	seq, err := pls.writeAhead(JournalRecord{Type: JournalPark, Park: &saved})
+	if err != nil {
+		return nil, err
+	}
+	if err := pls.admitTicket(ticket); err != nil {
+		return nil, pls.abortJournaled(seq, err)
+	}
+	return ticket.Copy(), nil
+}
 
-		// Occupy the spot
-		if err := spotCollection.OccupySpot(spotID, vehicle); err != nil {
-			continue // Failed to occupy, try next floor
+// admitTicket occupies a new ticket's spots and stores it as the vehicle's active ticket
+// Must be called with the lock held
+func (pls *ParkingLotService) admitTicket(ticket *entities.Ticket) error {
+	if ticket.FloorID < 1 || ticket.FloorID > len(pls.floors) {
+		return ErrInvalidFloor
+	}
+	spots := pls.floors[ticket.FloorID-1].GetSpotByVehicleType(ticket.SpotType)
+	if spots == nil {
+		return errors.New("invalid spot collection")
+	}
+
+	// Hand the reservation's hold over to the vehicle, putting it back if the ticket can't be admitted
+	reservation := pls.reservations[ticket.ReservationID]
+	restoreHold := func() error { return nil }
+	if reservation != nil && reservation.Status == entities.ReservationHolding {
+		held := pls.floors[reservation.FloorID-1].GetSpotByVehicleType(reservation.SpotType)
+		if err := held.UnreserveSpots(reservation.SpotIDs); err != nil {
+			return fmt.Errorf("failed to release reservation hold: %w", err)
 		}
+		restoreHold = func() error {
+			if err := held.ReserveSpots(reservation.SpotIDs); err != nil {
+				return fmt.Errorf("failed to restore reservation hold: %w", err)
+			}
+			return nil
+		}
+	}
 
-		// Create ticket
-		pricePerHour := pls.pricing[vehicleType]
-		ticket := entities.NewTicket(vehicle, floor.ID, spotID, pricePerHour)
+	// Occupy every spot of the run
+	if err := spots.OccupySpots(ticket.SpotIDs, ticket.Vehicle); err != nil {
+		return errors.Join(fmt.Errorf("failed to occupy spot: %w", err), restoreHold())
+	}
 
-		// Store ticket
-		pls.tickets[ticket.ID] = ticket
-		pls.activeTickets[vehicle.GetNumberPlate()] = ticket
+	// Store ticket, giving the spots back if it can't be stored
+	if err := pls.tickets.Save(ticket); err != nil {
+		spots.ReleaseSpots(ticket.SpotIDs)
+		return errors.Join(fmt.Errorf("failed to store ticket: %w", err), restoreHold())
+	}
+	if reservation != nil {
+		reservation.TicketID = ticket.ID
+		reservation.Status = entities.ReservationFulfilled
+	}
+	pls.emit(VehicleParked{At: ticket.EntryTime, Ticket: *ticket})
+	return nil
+}
 
-		return ticket, nil
+// allocate finds spots for a vehicle without occupying them
+// It returns the floor, the spot kind and the IDs of the adjacent spots chosen
+func (pls *ParkingLotService) allocate(vehicle entities.Vehicle, requirements entities.SpotRequirements) (*entities.ParkingSpace, entities.VehicleType, []int, error) {
+	spotsRequired := entities.SpotsRequired(vehicle)
+
+	passes := []entities.SpotRequirements{requirements}
+	if requirements.Prefer != 0 {
+		passes = []entities.SpotRequirements{requirements.Preferred(), requirements}
 	}
 
-	return nil, ErrParkingLotFull
+	for _, pass := range passes {
+		for _, spotType := range pls.compatibility.SpotKinds(vehicle.Type()) {
+			req := AllocationRequest{SpotType: spotType, Spots: spotsRequired, Requirements: pass}
+			floor, firstSpotID, err := pls.strategy.SelectSpot(pls.floors, req)
+			if errors.Is(err, ErrParkingLotFull) {
+				continue // No spot of this kind, try the next compatible kind
+			}
+			if err != nil {
+				return nil, 0, nil, err
+			}
+			return floor, spotType, spotIDs(firstSpotID, spotsRequired), nil
+		}
+	}
+
+	return nil, 0, nil, ErrParkingLotFull
+}
+
+// rateFor returns the hourly rate for a vehicle parked in a spot of the given kind
+func (pls *ParkingLotService) rateFor(vehicleType, spotType entities.VehicleType) int {
+	if pls.fallbackPricing == SpotRate && entities.IsFallbackSpot(vehicleType, spotType) {
+		return pls.hourlyRate(spotType)
+	}
+	return pls.hourlyRate(vehicleType)
+}
+
+// hourlyRate returns the configured price per hour for a type, falling back to its registered default
+func (pls *ParkingLotService) hourlyRate(vehicleType entities.VehicleType) int {
+	if rate, ok := pls.pricing[vehicleType]; ok {
+		return rate
+	}
+	if info, ok := entities.LookupVehicleType(vehicleType); ok {
+		return info.DefaultRate
+	}
+	return 0
 }
 
 // UnparkVehicle releases a vehicle and calculates the final price
+// The price comes from the pricing policy the ticket was issued under
+// The ticket must be paid first; ErrPaymentRequired is returned while anything is still due
 func (pls *ParkingLotService) UnparkVehicle(ticketID string) (*entities.Ticket, int, error) {
+	return pls.UnparkVehicleWithOptions(ticketID, UnparkOptions{})
+}
+
+// UnparkVehicleWithOptions releases a vehicle like UnparkVehicle, recording the exit details on its ticket
+func (pls *ParkingLotService) UnparkVehicleWithOptions(ticketID string, opts UnparkOptions) (*entities.Ticket, int, error) {
+	if err := pls.checkGate(opts.ExitGateID, GateExit); err != nil {
+		return nil, 0, err
+	}
+
+	defer pls.publishEvents()
 	pls.mu.Lock()
 	defer pls.mu.Unlock()
 
-	ticket, exists := pls.tickets[ticketID]
-	if !exists {
-		return nil, 0, ErrTicketNotFound
+	ticket, err := pls.tickets.Get(ticketID)
+	if err != nil {
+		return nil, 0, err
 	}
 
+	if ticket.IsVoided() {
+		return nil, 0, fmt.Errorf("%w: use %s", ErrTicketVoided, ticket.ReplacedByID)
+	}
 	if !ticket.IsActive() {
-		return ticket, ticket.CalculatePrice(), nil // Already unparked, return existing price
+		return ticket.Copy(), ticket.Fee, nil // Already unparked, return existing price
 	}
 
-	// Validate floor and spot
+	// Validate floor, spot and pricing before changing anything
 	if ticket.FloorID < 1 || ticket.FloorID > len(pls.floors) {
 		return nil, 0, ErrInvalidFloor
 	}
+	if _, ok := pls.policies[ticket.PolicyVersion]; !ok {
+		return nil, 0, fmt.Errorf("%w: %s", ErrUnknownPricingPolicy, ticket.PolicyVersion)
+	}
+
+	exitTime := pls.clock.Now()
+	price, err := pls.exitFee(ticket, exitTime)
+	if err != nil {
+		return nil, 0, err
+	}
+	if due := price - ticket.PaidAmount; due > 0 {
+		return nil, 0, fmt.Errorf("%w: %d due", ErrPaymentRequired, due)
+	}
 
+	exit := ExitRecord{TicketID: ticket.ID, ExitTime: exitTime, Fee: price, ExitGateID: opts.ExitGateID}
+	seq, err := pls.writeAhead(JournalRecord{Type: JournalUnpark, Unpark: &exit})
+	if err != nil {
+		return nil, 0, err
+	}
+	if err := pls.checkOut(ticket, exit); err != nil {
+		return nil, 0, pls.abortJournaled(seq, err)
+	}
+	return ticket.Copy(), price, nil
+}
+
+// checkOut releases a ticket's spots and closes it with the exit details
+// If either step fails the ticket is left active in its spots
+// Must be called with the lock held
+func (pls *ParkingLotService) checkOut(ticket *entities.Ticket, exit ExitRecord) error {
 	floor := pls.floors[ticket.FloorID-1]
-	spotCollection := floor.GetSpotByVehicleType(ticket.VehicleType)
+	spotCollection := floor.GetSpotByVehicleType(ticket.SpotType)
 
 	if spotCollection == nil {
-		return nil, 0, errors.New("invalid spot collection")
+		return errors.New("invalid spot collection")
+	}
+
+	// Mark ticket as exited with the price that was paid, which also ends it being active
+	ticket.MarkExit(exit.ExitTime)
+	ticket.Fee = exit.Fee
+	ticket.ExitGateID = exit.ExitGateID
+	if err := pls.tickets.Save(ticket); err != nil {
+		ticket.ExitTime, ticket.Fee, ticket.ExitGateID = time.Time{}, 0, ""
+		return fmt.Errorf("failed to store ticket: %w", err)
 	}
 
-	// Release the spot
-	if err := spotCollection.ReleaseSpot(ticket.SpotID); err != nil {
-		return nil, 0, fmt.Errorf("failed to release spot: %w", err)
+	// Release every spot held by the ticket together, storing the ticket as active again if they can't be
+	if err := spotCollection.ReleaseSpots(ticket.SpotIDs); err != nil {
+		ticket.ExitTime, ticket.Fee, ticket.ExitGateID = time.Time{}, 0, ""
+		err = fmt.Errorf("failed to release spot: %w", err)
+		if saveErr := pls.tickets.Save(ticket); saveErr != nil {
+			return errors.Join(err, fmt.Errorf("failed to store ticket: %w", saveErr))
+		}
+		return err
 	}
 
-	// Mark ticket as exited
-	ticket.MarkExit()
+	pls.emit(SpotReleased{At: exit.ExitTime, FloorID: floor.ID, SpotType: ticket.SpotType, SpotIDs: ticket.SpotIDs})
+	pls.emit(VehicleExited{At: exit.ExitTime, Ticket: *ticket, Fee: exit.Fee})
+	return nil
+}
 
-	// Remove from active tickets
-	delete(pls.activeTickets, ticket.Vehicle.GetNumberPlate())
+// ConfigureSpots sets the attributes of spots on a floor
+// chargerKW is the charger power rating and only applies when attributes include EVCharger
+func (pls *ParkingLotService) ConfigureSpots(floorID int, spotType entities.VehicleType, spotIDs []int, attributes entities.SpotAttributes, chargerKW float64) error {
+	pls.mu.Lock()
+	defer pls.mu.Unlock()
 
-	// Calculate final price
-	price := ticket.CalculatePrice()
+	if floorID < 1 || floorID > len(pls.floors) {
+		return ErrInvalidFloor
+	}
 
-	return ticket, price, nil
+	spots, ok := pls.floors[floorID-1].Spots[spotType]
+	if !ok {
+		return fmt.Errorf("floor %d has no %s spots: %w", floorID, spotType, entities.ErrSpotNotFound)
+	}
+	for _, spotID := range spotIDs {
+		if err := spots.SetSpotAttributes(spotID, attributes, chargerKW); err != nil {
+			return fmt.Errorf("spot %d: %w", spotID, err)
+		}
+	}
+	return nil
 }
 
-// GetTicket retrieves a ticket by ID
+// GetTicket retrieves a copy of a ticket by ID
 func (pls *ParkingLotService) GetTicket(ticketID string) (*entities.Ticket, error) {
 	pls.mu.RLock()
 	defer pls.mu.RUnlock()
 
-	ticket, exists := pls.tickets[ticketID]
-	if !exists {
-		return nil, ErrTicketNotFound
+	ticket, err := pls.tickets.Get(ticketID)
+	if err != nil {
+		return nil, err
 	}
-
-	return ticket, nil
+	return ticket.Copy(), nil
 }
 
-// GetActiveTicketByVehicle retrieves active ticket for a vehicle
+// GetActiveTicketByVehicle retrieves a copy of the active ticket for a vehicle
 func (pls *ParkingLotService) GetActiveTicketByVehicle(numberPlate string) (*entities.Ticket, error) {
 	pls.mu.RLock()
 	defer pls.mu.RUnlock()
 
-	ticket, exists := pls.activeTickets[numberPlate]
-	if !exists {
-		return nil, ErrVehicleNotParked
+	ticket, err := pls.tickets.GetActiveByPlate(numberPlate)
+	if err != nil {
+		return nil, err
 	}
+	return ticket.Copy(), nil
+}
 
-	return ticket, nil
+// checkNotParked returns an error if the vehicle already has an active ticket
+// Must be called with the lock held
+func (pls *ParkingLotService) checkNotParked(numberPlate string) error {
+	_, err := pls.tickets.GetActiveByPlate(numberPlate)
+	if err == nil {
+		return fmt.Errorf("%w: %s", ErrVehicleAlreadyParked, numberPlate)
+	}
+	if errors.Is(err, ErrVehicleNotParked) {
+		return nil
+	}
+	return err
 }
 
 // GetParkingLotStatus returns the current status of the parking lot
@@ -174,52 +469,77 @@ func (pls *ParkingLotService) GetParkingLotStatus() *ParkingLotStatus {
 	defer pls.mu.RUnlock()
 
 	status := &ParkingLotStatus{
-		Floors: make([]FloorStatus, len(pls.floors)),
-		TotalActiveTickets: len(pls.activeTickets),
+		Floors:             make([]FloorStatus, len(pls.floors)),
+		TotalActiveTickets: pls.tickets.CountActive(),
+		EventSeq:           pls.eventSeq,
 	}
 
 	for i, floor := range pls.floors {
-		status.Floors[i] = FloorStatus{
+		floorStatus := FloorStatus{
 			FloorID: floor.ID,
-			CarSpots: SpotStatus{
-				Total:    floor.CarSpots.GetTotalSpots(),
-				Occupied: floor.CarSpots.GetOccupiedCount(),
-				Vacant:   floor.CarSpots.GetVacantCount(),
-			},
-			MotorcycleSpots: SpotStatus{
-				Total:    floor.MotorBikeSpots.GetTotalSpots(),
-				Occupied: floor.MotorBikeSpots.GetOccupiedCount(),
-				Vacant:   floor.MotorBikeSpots.GetVacantCount(),
-			},
-			TruckSpots: SpotStatus{
-				Total:    floor.TruckSpots.GetTotalSpots(),
-				Occupied: floor.TruckSpots.GetOccupiedCount(),
-				Vacant:   floor.TruckSpots.GetVacantCount(),
-			},
+			Name:    floor.Name,
+			Closed:  floor.Closed,
+			Spots:   make(map[entities.VehicleType]SpotStatus, len(floor.Spots)),
+		}
+		for spotType, spots := range floor.Spots {
+			floorStatus.Spots[spotType] = spotStatus(spots)
 		}
+		status.Floors[i] = floorStatus
 	}
 
 	return status
 }
 
+// SpotKinds returns the spot kinds present on the floor, in vehicle type registration order
+func (fs FloorStatus) SpotKinds() []entities.VehicleType {
+	kinds := make([]entities.VehicleType, 0, len(fs.Spots))
+	for _, info := range entities.VehicleTypes() {
+		if _, ok := fs.Spots[info.Type]; ok {
+			kinds = append(kinds, info.Type)
+		}
+	}
+	return kinds
+}
+
+// spotStatus summarizes a spot collection, treating a missing collection as empty
+func spotStatus(spots entities.ParkingSpot) SpotStatus {
+	if spots == nil {
+		return SpotStatus{}
+	}
+	return SpotStatus{
+		Total:        spots.GetTotalSpots(),
+		Occupied:     spots.GetOccupiedCount(),
+		Vacant:       spots.GetVacantCount(),
+		Reserved:     spots.GetReservedCount(),
+		OutOfService: spots.GetStateCount(entities.SpotOutOfService),
+		Blocked:      spots.GetStateCount(entities.SpotBlocked),
+		Attributes:   spots.AttributeCounts(),
+	}
+}
+
 // ParkingLotStatus represents the current status of the parking lot
 type ParkingLotStatus struct {
-	Floors            []FloorStatus
+	Floors             []FloorStatus
 	TotalActiveTickets int
+	EventSeq           uint64 // Sequence number of the last event raised before the status was read
 }
 
 // FloorStatus represents the status of a single floor
+// Spots has an entry for every spot kind configured on the floor, including registered custom kinds
 type FloorStatus struct {
-	FloorID          int
-	CarSpots         SpotStatus
-	MotorcycleSpots  SpotStatus
-	TruckSpots       SpotStatus
+	FloorID int
+	Name    string
+	Closed  bool
+	Spots   map[entities.VehicleType]SpotStatus
 }
 
 // SpotStatus represents the status of spots of a particular type
 type SpotStatus struct {
-	Total    int
-	Occupied int
-	Vacant   int
+	Total        int
+	Occupied     int
+	Vacant       int
+	Reserved     int // Held for reservations; counted in neither Occupied nor Vacant
+	OutOfService int // Closed by an operator or with their floor
+	Blocked      int
+	Attributes   map[entities.SpotAttributes]entities.AttributeCount // availability per spot attribute
 }
-
@@ -0,0 +1,183 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"parkinglot/entities"
+)
+
+func TestAdmitTicketKeepsReservationHoldOnFailure(t *testing.T) {
+	clock := entities.NewManualClock(time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC))
+	pls := NewParkingLotService([][3]int{{2, 0, 0}}, nil, WithClock(clock))
+	reservation, err := pls.ReserveSpot(ReservationRequest{
+		NumberPlate: "BOOKED-1",
+		VehicleType: entities.CAR,
+		Start:       clock.Now(),
+		End:         clock.Now().Add(2 * time.Hour),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A ticket for spots that can't be occupied must leave the reservation's hold in place
+	ticket := entities.NewTicket(entities.NewCar("BOOKED-1"), 1, entities.CAR, []int{3}, 20, clock.Now())
+	ticket.ReservationID = reservation.ID
+	pls.mu.Lock()
+	err = pls.admitTicket(ticket)
+	pls.mu.Unlock()
+	if err == nil {
+		t.Fatal("admitted a ticket for a spot that does not exist")
+	}
+
+	cars := pls.GetParkingLotStatus().Floors[0].Spots[entities.CAR]
+	if cars.Reserved != 1 || cars.Vacant != 1 {
+		t.Errorf("after the failed admission %d spots are reserved and %d vacant, want 1 and 1", cars.Reserved, cars.Vacant)
+	}
+}
+
+func TestReturnedTicketsAreCopies(t *testing.T) {
+	clock := entities.NewManualClock(time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC))
+	pls := NewParkingLotService([][3]int{{2, 0, 0}}, nil, WithClock(clock))
+	parked, err := pls.ParkVehicle(entities.NewCar("COPY-1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	spotID := parked.SpotIDs[0]
+
+	// Changing what the service handed out leaves the lot's own ticket alone
+	parked.PaidAmount = 999
+	parked.SpotIDs[0] = 99
+	fetched, err := pls.GetTicket(parked.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fetched == parked || fetched.PaidAmount != 0 || fetched.SpotIDs[0] != spotID {
+		t.Errorf("GetTicket returned %+v after changing the parked copy, want the ticket as issued", fetched)
+	}
+	if active, _ := pls.GetActiveTicketByVehicle("COPY-1"); active == fetched {
+		t.Error("GetActiveTicketByVehicle and GetTicket returned the same ticket value")
+	}
+
+	// Nor do copies handed out earlier follow later changes
+	if _, err := pls.PayTicket(parked.ID, entities.PaymentCash, 0); err != nil {
+		t.Fatal(err)
+	}
+	if fetched.PaidAmount != 0 {
+		t.Errorf("copy fetched before paying shows %d paid, want 0", fetched.PaidAmount)
+	}
+	exited, _, err := pls.UnparkVehicle(parked.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	exited.ExitTime = time.Time{}
+	if again, _ := pls.GetTicket(parked.ID); again.IsActive() {
+		t.Error("clearing the exit time on the unparked copy reopened the lot's ticket")
+	}
+}
+
+// benchmarkSizes are total spot counts from a small car park to the largest garages
+var benchmarkSizes = []int{1_000, 10_000, 100_000}
+
+// newBenchmarkLot creates a ten-floor lot with its spots split 70/20/10 between cars, motorcycles
+// and trucks, and half its car spots taken
+func newBenchmarkLot(b *testing.B, spots int) *ParkingLotService {
+	b.Helper()
+	perFloor := spots / 10
+	floors := make([][3]int, 10)
+	for i := range floors {
+		floors[i] = [3]int{perFloor * 7 / 10, perFloor * 2 / 10, perFloor / 10}
+	}
+	clock := entities.NewManualClock(time.Date(2026, 3, 2, 14, 0, 0, 0, time.UTC))
+	pls := NewParkingLotService(floors, nil, WithClock(clock))
+
+	for i := 0; i < spots*7/10/2; i++ {
+		if _, err := pls.ParkVehicle(entities.NewCar(fmt.Sprintf("RESIDENT-%d", i))); err != nil {
+			b.Fatal(err)
+		}
+	}
+	return pls
+}
+
+// benchmarkCars creates n cars with distinct number plates
+func benchmarkCars(n int) []entities.Vehicle {
+	cars := make([]entities.Vehicle, n)
+	for i := range cars {
+		cars[i] = entities.NewCar(fmt.Sprintf("VISITOR-%d", i))
+	}
+	return cars
+}
+
+func BenchmarkParkVehicle(b *testing.B) {
+	for _, spots := range benchmarkSizes {
+		b.Run(fmt.Sprintf("spots=%d", spots), func(b *testing.B) {
+			cars := benchmarkCars(b.N)
+			pls := newBenchmarkLot(b, spots)
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				_, err := pls.ParkVehicle(cars[i])
+				if errors.Is(err, ErrParkingLotFull) {
+					// Start over on a half-full lot rather than measuring rejections
+					b.StopTimer()
+					pls = newBenchmarkLot(b, spots)
+					b.StartTimer()
+					_, err = pls.ParkVehicle(cars[i])
+				}
+				if err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkUnparkVehicle(b *testing.B) {
+	for _, spots := range benchmarkSizes {
+		b.Run(fmt.Sprintf("spots=%d", spots), func(b *testing.B) {
+			cars := benchmarkCars(b.N)
+			pls := newBenchmarkLot(b, spots)
+			b.ResetTimer()
+			for parked := 0; parked < b.N; {
+				// Park and pay for as many cars as fit, then time only their exits
+				b.StopTimer()
+				var ticketIDs []string
+				for ; parked < b.N; parked++ {
+					ticket, err := pls.ParkVehicle(cars[parked])
+					if errors.Is(err, ErrParkingLotFull) {
+						break
+					}
+					if err != nil {
+						b.Fatal(err)
+					}
+					if _, err := pls.PayTicket(ticket.ID, entities.PaymentCash, 0); err != nil {
+						b.Fatal(err)
+					}
+					ticketIDs = append(ticketIDs, ticket.ID)
+				}
+				b.StartTimer()
+
+				for _, ticketID := range ticketIDs {
+					if _, _, err := pls.UnparkVehicle(ticketID); err != nil {
+						b.Fatal(err)
+					}
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkGetParkingLotStatus(b *testing.B) {
+	for _, spots := range benchmarkSizes {
+		b.Run(fmt.Sprintf("spots=%d", spots), func(b *testing.B) {
+			pls := newBenchmarkLot(b, spots)
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if status := pls.GetParkingLotStatus(); status.TotalActiveTickets == 0 {
+					b.Fatal("no active tickets")
+				}
+			}
+		})
+	}
+}
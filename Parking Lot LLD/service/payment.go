@@ -0,0 +1,277 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"parkinglot/entities"
+)
+
+var (
+	ErrPaymentRequired    = errors.New("ticket must be paid before exit")
+	ErrPaymentFailed      = errors.New("payment failed")
+	ErrNoPaymentProcessor = errors.New("no processor for payment method")
+	ErrNothingToPay       = errors.New("ticket has nothing left to pay")
+	ErrInsufficientFunds  = errors.New("insufficient wallet balance")
+	ErrCardDeclined       = errors.New("card declined")
+)
+
+// PaymentProcessor collects money for a ticket through one payment method
+type PaymentProcessor interface {
+	Method() entities.PaymentMethod
+	// Charge collects amount cents for the ticket and returns a processor reference
+	Charge(ticket *entities.Ticket, amount int) (string, error)
+}
+
+// CashProcessor stands in for a cash machine; cash payments always succeed
+type CashProcessor struct {
+	receipts int
+	mu       sync.Mutex
+}
+
+// NewCashProcessor creates a new CashProcessor
+func NewCashProcessor() *CashProcessor {
+	return &CashProcessor{}
+}
+
+func (p *CashProcessor) Method() entities.PaymentMethod {
+	return entities.PaymentCash
+}
+
+func (p *CashProcessor) Charge(ticket *entities.Ticket, amount int) (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.receipts++
+	return fmt.Sprintf("CASH-%06d", p.receipts), nil
+}
+
+// CardProcessor stands in for a card terminal
+// It declines charges above DeclineAbove, and the next FailNext charges, so failures and retries can be simulated
+type CardProcessor struct {
+	DeclineAbove int // Charges above this amount are declined; 0 means no limit
+	FailNext     int // Number of upcoming charges to decline regardless of amount
+	approvals    int
+	mu           sync.Mutex
+}
+
+// NewCardProcessor creates a CardProcessor that approves every charge
+func NewCardProcessor() *CardProcessor {
+	return &CardProcessor{}
+}
+
+func (p *CardProcessor) Method() entities.PaymentMethod {
+	return entities.PaymentCard
+}
+
+func (p *CardProcessor) Charge(ticket *entities.Ticket, amount int) (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.FailNext > 0 {
+		p.FailNext--
+		return "", ErrCardDeclined
+	}
+	if p.DeclineAbove > 0 && amount > p.DeclineAbove {
+		return "", fmt.Errorf("%w: amount %d over limit", ErrCardDeclined, amount)
+	}
+
+	p.approvals++
+	return fmt.Sprintf("AUTH-%06d", p.approvals), nil
+}
+
+// WalletProcessor stands in for a prepaid wallet keyed by vehicle number plate
+type WalletProcessor struct {
+	balances map[string]int
+	charges  int
+	mu       sync.Mutex
+}
+
+// NewWalletProcessor creates a WalletProcessor with no balances
+func NewWalletProcessor() *WalletProcessor {
+	return &WalletProcessor{balances: make(map[string]int)}
+}
+
+func (p *WalletProcessor) Method() entities.PaymentMethod {
+	return entities.PaymentWallet
+}
+
+// TopUp adds funds to the wallet of a vehicle
+func (p *WalletProcessor) TopUp(numberPlate string, amount int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.balances[numberPlate] += amount
+}
+
+// Balance returns the wallet balance of a vehicle
+func (p *WalletProcessor) Balance(numberPlate string) int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.balances[numberPlate]
+}
+
+func (p *WalletProcessor) Charge(ticket *entities.Ticket, amount int) (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	plate := ticket.Vehicle.GetNumberPlate()
+	if p.balances[plate] < amount {
+		return "", fmt.Errorf("%w: balance %d, needed %d", ErrInsufficientFunds, p.balances[plate], amount)
+	}
+
+	p.balances[plate] -= amount
+	p.charges++
+	return fmt.Sprintf("WLT-%06d", p.charges), nil
+}
+
+// WithPaymentProcessor registers a processor, replacing any existing one for the same method
+// Cash, card and wallet stand-ins are registered by default
+func WithPaymentProcessor(processor PaymentProcessor) Option {
+	return func(pls *ParkingLotService) {
+		if processor != nil {
+			pls.processors[processor.Method()] = processor
+		}
+	}
+}
+
+// WithExitGracePeriod sets how long a driver has to leave after paying in full
+// before the extra time is charged; defaults to 15 minutes
+func WithExitGracePeriod(grace time.Duration) Option {
+	return func(pls *ParkingLotService) {
+		pls.exitGrace = grace
+	}
+}
+
+// defaultPaymentProcessors returns the stand-in processors registered for every new service
+func defaultPaymentProcessors() map[entities.PaymentMethod]PaymentProcessor {
+	return map[entities.PaymentMethod]PaymentProcessor{
+		entities.PaymentCash:   NewCashProcessor(),
+		entities.PaymentCard:   NewCardProcessor(),
+		entities.PaymentWallet: NewWalletProcessor(),
+	}
+}
+
+// PaymentProcessor returns the processor registered for a payment method
+func (pls *ParkingLotService) PaymentProcessor(method entities.PaymentMethod) (PaymentProcessor, bool) {
+	pls.mu.RLock()
+	defer pls.mu.RUnlock()
+
+	processor, ok := pls.processors[method]
+	return processor, ok
+}
+
+// PayTicket pays toward a ticket's fee as it stands now
+// An amount of 0 pays the full outstanding balance; a smaller amount is a partial payment
+// Every attempt is recorded, and a failed attempt returns its Payment along with ErrPaymentFailed
+// so the caller can retry with the same or another method
+// With a journal, an attempt that could not be journaled still stands, but ErrJournal is returned
+// because it would be lost in a crash before the next snapshot
+func (pls *ParkingLotService) PayTicket(ticketID string, method entities.PaymentMethod, amount int) (*entities.Payment, error) {
+	pls.mu.Lock()
+	defer pls.mu.Unlock()
+
+	ticket, err := pls.tickets.Get(ticketID)
+	if err != nil {
+		return nil, err
+	}
+	if ticket.IsVoided() {
+		return nil, fmt.Errorf("%w: use %s", ErrTicketVoided, ticket.ReplacedByID)
+	}
+	if !ticket.IsActive() {
+		return nil, ErrNothingToPay
+	}
+
+	processor, ok := pls.processors[method]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrNoPaymentProcessor, method)
+	}
+
+	now := pls.clock.Now()
+	fee, err := pls.priceTicket(ticket, now)
+	if err != nil {
+		return nil, err
+	}
+	due := fee - ticket.PaidAmount
+	if due <= 0 {
+		return nil, ErrNothingToPay
+	}
+	if amount <= 0 || amount > due {
+		amount = due
+	}
+
+	payment := entities.NewPayment(ticket.ID, amount, method, len(pls.payments[ticket.ID])+1, now)
+	pls.payments[ticket.ID] = append(pls.payments[ticket.ID], payment)
+
+	reference, chargeErr := processor.Charge(ticket, amount)
+	if chargeErr != nil {
+		payment.Fail(chargeErr.Error())
+	} else {
+		payment.Complete(reference)
+		ticket.PaidAmount += amount
+		if ticket.PaidAmount >= fee {
+			ticket.PaidAt = now
+		}
+		if err := pls.tickets.Save(ticket); err != nil {
+			return payment, fmt.Errorf("failed to store ticket: %w", err)
+		}
+	}
+
+	// The charge cannot be taken back, so the attempt is journaled after it happened
+	paid := PaymentRecord{Payment: PaymentSnapshot(*payment), PaidAmount: ticket.PaidAmount, PaidAt: ticket.PaidAt}
+	if _, err := pls.writeAhead(JournalRecord{Type: JournalPay, Pay: &paid}); err != nil {
+		return payment, err
+	}
+	if chargeErr != nil {
+		return payment, fmt.Errorf("%w: %v", ErrPaymentFailed, chargeErr)
+	}
+	return payment, nil
+}
+
+// AmountDue returns what is left to pay on a ticket if the vehicle were to exit now
+func (pls *ParkingLotService) AmountDue(ticketID string) (int, error) {
+	pls.mu.RLock()
+	defer pls.mu.RUnlock()
+
+	ticket, err := pls.tickets.Get(ticketID)
+	if err != nil {
+		return 0, err
+	}
+	if ticket.IsVoided() {
+		return 0, fmt.Errorf("%w: use %s", ErrTicketVoided, ticket.ReplacedByID)
+	}
+	if !ticket.IsActive() {
+		return 0, nil
+	}
+
+	fee, err := pls.exitFee(ticket, pls.clock.Now())
+	if err != nil {
+		return 0, err
+	}
+	if due := fee - ticket.PaidAmount; due > 0 {
+		return due, nil
+	}
+	return 0, nil
+}
+
+// GetPayments returns every payment attempt for a ticket, oldest first
+func (pls *ParkingLotService) GetPayments(ticketID string) ([]*entities.Payment, error) {
+	pls.mu.RLock()
+	defer pls.mu.RUnlock()
+
+	if _, err := pls.tickets.Get(ticketID); err != nil {
+		return nil, err
+	}
+	return append([]*entities.Payment(nil), pls.payments[ticketID]...), nil
+}
+
+// exitFee returns the fee owed by a vehicle leaving at exitTime
+// A driver who paid in full and leaves within the exit grace period owes the fee as of the payment
+func (pls *ParkingLotService) exitFee(ticket *entities.Ticket, exitTime time.Time) (int, error) {
+	until := exitTime
+	if !ticket.PaidAt.IsZero() && exitTime.Sub(ticket.PaidAt) <= pls.exitGrace {
+		until = ticket.PaidAt
+	}
+	return pls.priceTicket(ticket, until)
+}
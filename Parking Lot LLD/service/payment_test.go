@@ -0,0 +1,124 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"parkinglot/entities"
+)
+
+// newPaymentLot creates a one-floor lot with a car parked 90 minutes ago, so 40 is due at the
+// default car rate of 20 an hour
+func newPaymentLot(t *testing.T, opts ...Option) (*ParkingLotService, *entities.ManualClock, *entities.Ticket) {
+	t.Helper()
+	clock := entities.NewManualClock(time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC))
+	pls := NewParkingLotService([][3]int{{2, 0, 0}}, nil, append([]Option{WithClock(clock)}, opts...)...)
+	ticket, err := pls.ParkVehicle(entities.NewCar("PAY-1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	clock.Advance(90 * time.Minute)
+	return pls, clock, ticket
+}
+
+func TestPartialPaymentThenRemainder(t *testing.T) {
+	pls, _, ticket := newPaymentLot(t)
+
+	partial, err := pls.PayTicket(ticket.ID, entities.PaymentCash, 15)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if partial.Status != entities.PaymentCompleted || partial.Amount != 15 {
+		t.Errorf("partial payment is %s for %d, want completed for 15", partial.Status, partial.Amount)
+	}
+	if due, err := pls.AmountDue(ticket.ID); err != nil || due != 25 {
+		t.Errorf("AmountDue after paying 15 = %d, %v; want 25", due, err)
+	}
+	if _, _, err := pls.UnparkVehicle(ticket.ID); !errors.Is(err, ErrPaymentRequired) {
+		t.Fatalf("exit with 25 still due: got %v, want ErrPaymentRequired", err)
+	}
+
+	// An amount of 0, or more than is due, pays exactly the remainder
+	remainder, err := pls.PayTicket(ticket.ID, entities.PaymentCard, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if remainder.Amount != 25 || remainder.Attempt != 2 {
+		t.Errorf("remainder paid %d on attempt %d, want 25 on attempt 2", remainder.Amount, remainder.Attempt)
+	}
+	if _, err := pls.PayTicket(ticket.ID, entities.PaymentCash, 0); !errors.Is(err, ErrNothingToPay) {
+		t.Errorf("paying a settled ticket: got %v, want ErrNothingToPay", err)
+	}
+
+	exited, fee, err := pls.UnparkVehicle(ticket.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fee != 40 || exited.PaidAmount != 40 {
+		t.Errorf("exited with fee %d and %d paid, want 40 and 40", fee, exited.PaidAmount)
+	}
+}
+
+func TestFailedPaymentThenRetry(t *testing.T) {
+	pls, _, ticket := newPaymentLot(t, WithPaymentProcessor(&CardProcessor{FailNext: 1}))
+
+	failed, err := pls.PayTicket(ticket.ID, entities.PaymentCard, 0)
+	if !errors.Is(err, ErrPaymentFailed) {
+		t.Fatalf("declined card: got %v, want ErrPaymentFailed", err)
+	}
+	if failed == nil || failed.Status != entities.PaymentFailed || failed.FailureReason == "" || failed.Reference != "" {
+		t.Fatalf("declined card returned %+v, want a failed payment with a reason and no reference", failed)
+	}
+	if due, _ := pls.AmountDue(ticket.ID); due != 40 {
+		t.Errorf("AmountDue after a failed payment = %d, want 40", due)
+	}
+	if _, _, err := pls.UnparkVehicle(ticket.ID); !errors.Is(err, ErrPaymentRequired) {
+		t.Errorf("exit after a failed payment: got %v, want ErrPaymentRequired", err)
+	}
+
+	retry, err := pls.PayTicket(ticket.ID, entities.PaymentCard, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if retry.Status != entities.PaymentCompleted || retry.Reference == "" || retry.Amount != 40 {
+		t.Errorf("retry is %+v, want a completed payment of 40 with a reference", retry)
+	}
+
+	payments, err := pls.GetPayments(ticket.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(payments) != 2 || payments[0].Status != entities.PaymentFailed || payments[1].Status != entities.PaymentCompleted {
+		t.Errorf("payment history %+v, want the failed attempt followed by the completed one", payments)
+	}
+	if _, _, err := pls.UnparkVehicle(ticket.ID); err != nil {
+		t.Errorf("exit after the retry: %v", err)
+	}
+}
+
+func TestExitGraceAfterPayment(t *testing.T) {
+	pls, clock, ticket := newPaymentLot(t, WithExitGracePeriod(15*time.Minute))
+	if _, err := pls.PayTicket(ticket.ID, entities.PaymentCash, 0); err != nil {
+		t.Fatal(err)
+	}
+
+	// Still inside the third hour, but after the grace period the extra time is due
+	clock.Advance(20 * time.Minute)
+	if _, _, err := pls.UnparkVehicle(ticket.ID); err != nil {
+		t.Fatalf("exit in the hour already paid for: %v", err)
+	}
+
+	other, err := pls.ParkVehicle(entities.NewCar("PAY-2"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	clock.Advance(50 * time.Minute)
+	if _, err := pls.PayTicket(other.ID, entities.PaymentCash, 0); err != nil {
+		t.Fatal(err)
+	}
+	clock.Advance(20 * time.Minute)
+	if _, _, err := pls.UnparkVehicle(other.ID); !errors.Is(err, ErrPaymentRequired) {
+		t.Errorf("exit 20 minutes after paying, into an unpaid hour: got %v, want ErrPaymentRequired", err)
+	}
+}
@@ -0,0 +1,246 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"parkinglot/entities"
+)
+
+var ErrUnknownPricingPolicy = errors.New("unknown pricing policy version")
+
+// PricingPolicy computes the fee for a parking stay
+// Every policy has a version; tickets record the version they were issued under so that
+// old tickets keep being priced the same way after the lot switches to a new policy
+type PricingPolicy interface {
+	Version() string
+	// Price returns the fee in cents for the ticket's stay from its entry time until the given time
+	Price(ticket *entities.Ticket, until time.Time) int
+}
+
+// HourlyPolicy rounds the stay up to whole hours, with a minimum of one hour, at the ticket's hourly rate
+// This is the original pricing of the parking lot and the default policy
+type HourlyPolicy struct{}
+
+// NewHourlyPolicy creates a new HourlyPolicy
+func NewHourlyPolicy() *HourlyPolicy {
+	return &HourlyPolicy{}
+}
+
+func (p *HourlyPolicy) Version() string {
+	return "hourly-v1"
+}
+
+func (p *HourlyPolicy) Price(ticket *entities.Ticket, until time.Time) int {
+	hours := billableUnits(until.Sub(ticket.EntryTime), time.Hour)
+	if hours == 0 {
+		hours = 1 // Minimum 1 hour charge
+	}
+	return hours * ticket.PricePerHour
+}
+
+// TariffPolicy is a configurable tariff built on the ticket's hourly rate
+// It supports a free grace period, a separate first-hour rate, billing increments shorter
+// than an hour and a cap on what each 24 hours of a stay can cost
+type TariffPolicy struct {
+	VersionID        string
+	GracePeriod      time.Duration // Stays no longer than this are free
+	FirstHourPercent int           // First hour rate as a percentage of the hourly rate; 0 means 100
+	Increment        time.Duration // Billing unit, e.g. 15 minutes; 0 means one hour
+	DailyCapHours    int           // Most a 24 hour period can cost, in hours of the hourly rate; 0 means no cap
+}
+
+func (p *TariffPolicy) Version() string {
+	return p.VersionID
+}
+
+func (p *TariffPolicy) Price(ticket *entities.Ticket, until time.Time) int {
+	duration := until.Sub(ticket.EntryTime)
+	if duration <= p.GracePeriod {
+		return 0
+	}
+
+	total := 0
+	firstDay := true
+	for remaining := duration; remaining > 0; remaining -= 24 * time.Hour {
+		day := remaining
+		if day > 24*time.Hour {
+			day = 24 * time.Hour
+		}
+		total += p.priceDay(ticket.PricePerHour, day, firstDay)
+		firstDay = false
+	}
+	return total
+}
+
+// DailyMaximum returns the most one 24 hour period costs at the given hourly rate, or 0 if uncapped
+func (p *TariffPolicy) DailyMaximum(pricePerHour int) int {
+	return p.DailyCapHours * pricePerHour
+}
+
+// priceDay prices up to 24 hours of a stay; only the first day gets the first-hour rate
+func (p *TariffPolicy) priceDay(pricePerHour int, duration time.Duration, firstDay bool) int {
+	increment := p.Increment
+	if increment <= 0 {
+		increment = time.Hour
+	}
+
+	units := billableUnits(duration, increment)
+	firstHourUnits := 0
+	if firstDay {
+		firstHourUnits = billableUnits(time.Hour, increment)
+		if firstHourUnits > units {
+			firstHourUnits = units
+		}
+	}
+
+	firstHourRate := pricePerHour
+	if p.FirstHourPercent > 0 {
+		firstHourRate = pricePerHour * p.FirstHourPercent / 100
+	}
+
+	// Rates are per hour, so scale the billed time by its share of an hour, rounding the total up to a cent
+	// Seconds rather than minutes keep increments shorter than a minute from being billed as nothing
+	firstHourSeconds := billableUnits(time.Duration(firstHourUnits)*increment, time.Second)
+	restSeconds := billableUnits(time.Duration(units-firstHourUnits)*increment, time.Second)
+	amount := ceilDiv(firstHourRate*firstHourSeconds+pricePerHour*restSeconds, int(time.Hour/time.Second))
+
+	if dailyCap := p.DailyMaximum(pricePerHour); dailyCap > 0 && amount > dailyCap {
+		return dailyCap
+	}
+	return amount
+}
+
+// FlatRatePolicy charges a fixed amount per stay, or per started day when PerDay is set
+type FlatRatePolicy struct {
+	VersionID     string
+	Amounts       map[entities.VehicleType]int // Flat amount in cents for each vehicle type
+	DefaultAmount int                          // Used for vehicle types missing from Amounts
+	PerDay        bool
+}
+
+func (p *FlatRatePolicy) Version() string {
+	return p.VersionID
+}
+
+func (p *FlatRatePolicy) Price(ticket *entities.Ticket, until time.Time) int {
+	amount, ok := p.Amounts[ticket.VehicleType]
+	if !ok {
+		amount = p.DefaultAmount
+	}
+	if !p.PerDay {
+		return amount
+	}
+
+	days := billableUnits(until.Sub(ticket.EntryTime), 24*time.Hour)
+	if days == 0 {
+		days = 1
+	}
+	return days * amount
+}
+
+// billableUnits returns how many started units of the given size a duration covers
+func billableUnits(duration, unit time.Duration) int {
+	if duration <= 0 {
+		return 0
+	}
+	return int((duration + unit - 1) / unit)
+}
+
+// ceilDiv divides a by b, rounding up
+func ceilDiv(a, b int) int {
+	return (a + b - 1) / b
+}
+
+// WithPricingPolicy sets the policy used for new tickets
+// Defaults to HourlyPolicy
+// Like MustRegisterVehicleType, it panics on a policy that fails its own Validate method, such as a
+// RateSchedule with inverted or overlapping bands; NewParkingLotServiceFromConfig reports those as errors
+func WithPricingPolicy(policy PricingPolicy) Option {
+	return func(pls *ParkingLotService) {
+		if policy != nil {
+			mustValidatePolicy(policy)
+			pls.policies[policy.Version()] = policy
+			pls.policy = policy
+		}
+	}
+}
+
+// WithPricingHistory registers older policy versions so tickets issued under them can still be priced
+// It panics on an invalid policy like WithPricingPolicy does
+func WithPricingHistory(policies ...PricingPolicy) Option {
+	return func(pls *ParkingLotService) {
+		for _, policy := range policies {
+			if policy != nil {
+				mustValidatePolicy(policy)
+				pls.policies[policy.Version()] = policy
+			}
+		}
+	}
+}
+
+// SetPricingPolicy switches the policy used for new tickets
+// Tickets already issued keep being priced under the version they were issued with
+// A policy that fails its own Validate method is rejected and the current one stays in use
+func (pls *ParkingLotService) SetPricingPolicy(policy PricingPolicy) error {
+	if policy == nil {
+		return nil
+	}
+	if err := validatePolicy(policy); err != nil {
+		return err
+	}
+
+	pls.mu.Lock()
+	defer pls.mu.Unlock()
+
+	pls.policies[policy.Version()] = policy
+	pls.policy = policy
+	return nil
+}
+
+// validatePolicy runs the checks of policies that can validate their settings, such as RateSchedule
+func validatePolicy(policy PricingPolicy) error {
+	if validator, ok := policy.(interface{ Validate() error }); ok {
+		return validator.Validate()
+	}
+	return nil
+}
+
+// mustValidatePolicy panics if a policy fails validation
+func mustValidatePolicy(policy PricingPolicy) {
+	if err := validatePolicy(policy); err != nil {
+		panic(fmt.Errorf("pricing policy %s: %w", policy.Version(), err))
+	}
+}
+
+// QuotePrice returns the fee for a ticket
+// Active tickets are priced up to now; exited tickets are re-priced up to their exit time,
+// under the policy version they were issued with
+func (pls *ParkingLotService) QuotePrice(ticketID string) (int, error) {
+	pls.mu.RLock()
+	defer pls.mu.RUnlock()
+
+	ticket, err := pls.tickets.Get(ticketID)
+	if err != nil {
+		return 0, err
+	}
+	if ticket.IsVoided() {
+		return 0, fmt.Errorf("%w: use %s", ErrTicketVoided, ticket.ReplacedByID)
+	}
+
+	until := ticket.ExitTime
+	if ticket.IsActive() {
+		until = pls.clock.Now()
+	}
+	return pls.priceTicket(ticket, until)
+}
+
+// priceTicket prices a ticket under the policy version it was issued with, plus any lost-ticket penalty
+func (pls *ParkingLotService) priceTicket(ticket *entities.Ticket, until time.Time) (int, error) {
+	policy, ok := pls.policies[ticket.PolicyVersion]
+	if !ok {
+		return 0, fmt.Errorf("%w: %s", ErrUnknownPricingPolicy, ticket.PolicyVersion)
+	}
+	return policy.Price(ticket, until) + ticket.Penalty, nil
+}
@@ -0,0 +1,36 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"parkinglot/entities"
+)
+
+func TestTariffPolicyPrice(t *testing.T) {
+	entry := time.Date(2026, 3, 2, 8, 0, 0, 0, time.UTC)
+	ticket := &entities.Ticket{EntryTime: entry, PricePerHour: 360, VehicleType: entities.CAR}
+
+	tests := []struct {
+		name   string
+		policy TariffPolicy
+		stay   time.Duration
+		want   int
+	}{
+		{"within grace period", TariffPolicy{GracePeriod: 10 * time.Minute}, 5 * time.Minute, 0},
+		{"hourly by default", TariffPolicy{}, 61 * time.Minute, 720},
+		{"quarter hours", TariffPolicy{Increment: 15 * time.Minute}, 61 * time.Minute, 450},
+		{"discounted first hour", TariffPolicy{FirstHourPercent: 50, Increment: 15 * time.Minute}, 90 * time.Minute, 360},
+		{"daily cap", TariffPolicy{DailyCapHours: 4}, 10 * time.Hour, 1440},
+		{"daily cap per day", TariffPolicy{DailyCapHours: 4}, 26 * time.Hour, 1440 + 720},
+		{"half minutes", TariffPolicy{Increment: 30 * time.Second}, 45 * time.Second, 6},
+		{"seconds", TariffPolicy{Increment: time.Second}, 10 * time.Second, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.policy.Price(ticket, entry.Add(tt.stay)); got != tt.want {
+				t.Errorf("Price after %s = %d, want %d", tt.stay, got, tt.want)
+			}
+		})
+	}
+}
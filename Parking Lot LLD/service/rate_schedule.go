@@ -0,0 +1,189 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"parkinglot/entities"
+)
+
+var ErrInvalidRateSchedule = errors.New("invalid rate schedule")
+
+// TimeBand is a window of the day during which an hourly rate applies
+// Start and End are wall-clock offsets from midnight; End is exclusive and may be 24h
+type TimeBand struct {
+	Days  []time.Weekday // Days the band applies on; empty means every day
+	Start time.Duration
+	End   time.Duration
+	Rate  int // Price per hour in cents
+}
+
+// appliesOn reports whether the band is in effect on the given weekday
+func (b TimeBand) appliesOn(day time.Weekday) bool {
+	if len(b.Days) == 0 {
+		return true
+	}
+	for _, d := range b.Days {
+		if d == day {
+			return true
+		}
+	}
+	return false
+}
+
+// overlaps reports whether two bands cover some of the same time on a day they both apply on
+// With everyDay their days are ignored, as they are for holiday bands
+func (b TimeBand) overlaps(other TimeBand, everyDay bool) bool {
+	if b.Start >= other.End || other.Start >= b.End {
+		return false
+	}
+	if everyDay || len(b.Days) == 0 || len(other.Days) == 0 {
+		return true
+	}
+	for _, day := range b.Days {
+		if other.appliesOn(day) {
+			return true
+		}
+	}
+	return false
+}
+
+// RateSchedule prices stays from a calendar of time bands per vehicle type, like a tariff board
+// A stay crossing several bands is split at every band boundary and midnight, each part is
+// charged at its own band's rate, and the sum is rounded up to a cent at the end
+// On holidays, HolidayBands replace the regular bands for vehicle types that have them
+// Time not covered by any band is charged at the ticket's hourly rate
+type RateSchedule struct {
+	VersionID    string
+	Location     *time.Location // Time zone of the tariff board; nil means UTC
+	Bands        map[entities.VehicleType][]TimeBand
+	HolidayBands map[entities.VehicleType][]TimeBand
+	Holidays     []time.Time // Dates on which HolidayBands apply; only the date part is used
+}
+
+func (rs *RateSchedule) Version() string {
+	return rs.VersionID
+}
+
+// Validate checks that every band fits within a day and that no two bands of a vehicle type
+// cover the same time on the same day
+func (rs *RateSchedule) Validate() error {
+	check := func(kind string, bands map[entities.VehicleType][]TimeBand, everyDay bool) error {
+		for vehicleType, typeBands := range bands {
+			for i, band := range typeBands {
+				if band.Start < 0 || band.End > 24*time.Hour || band.Start >= band.End {
+					return fmt.Errorf("%w: %s band %d for %s: range %s-%s must be within 0-24h with start before end",
+						ErrInvalidRateSchedule, kind, i, vehicleType, band.Start, band.End)
+				}
+				if band.Rate < 0 {
+					return fmt.Errorf("%w: %s band %d for %s: rate must not be negative", ErrInvalidRateSchedule, kind, i, vehicleType)
+				}
+				for j, earlier := range typeBands[:i] {
+					if band.overlaps(earlier, everyDay) {
+						return fmt.Errorf("%w: %s band %d for %s overlaps band %d", ErrInvalidRateSchedule, kind, i, vehicleType, j)
+					}
+				}
+			}
+		}
+		return nil
+	}
+	if err := check("regular", rs.Bands, false); err != nil {
+		return err
+	}
+	// Holiday bands apply on every holiday, whatever days they list
+	return check("holiday", rs.HolidayBands, true)
+}
+
+func (rs *RateSchedule) Price(ticket *entities.Ticket, until time.Time) int {
+	location := rs.Location
+	if location == nil {
+		location = time.UTC
+	}
+
+	// Accumulate cent-seconds so that partial hours in each band are charged exactly
+	var centSeconds int64
+	for current := ticket.EntryTime.In(location); current.Before(until); {
+		year, month, day := current.Date()
+		nextMidnight := time.Date(year, month, day+1, 0, 0, 0, 0, location)
+		offset := time.Duration(current.Hour())*time.Hour +
+			time.Duration(current.Minute())*time.Minute +
+			time.Duration(current.Second())*time.Second +
+			time.Duration(current.Nanosecond())
+
+		rate := ticket.PricePerHour
+		segmentEnd := nextMidnight
+		bands := rs.bandsFor(ticket.VehicleType, current)
+		if band, ok := bandAt(bands, offset); ok {
+			rate = band.Rate
+			segmentEnd = wallClock(year, month, day, band.End, location)
+		} else if start, ok := nextBandStart(bands, offset); ok {
+			segmentEnd = wallClock(year, month, day, start, location)
+		}
+		if segmentEnd.After(nextMidnight) || !segmentEnd.After(current) {
+			segmentEnd = nextMidnight // Also guards against empty segments around DST changes
+		}
+		if segmentEnd.After(until) {
+			segmentEnd = until
+		}
+
+		centSeconds += int64(rate) * int64(segmentEnd.Sub(current)/time.Second)
+		current = segmentEnd
+	}
+
+	return int((centSeconds + 3599) / 3600)
+}
+
+// bandsFor returns the bands in effect for a vehicle type on the day of t
+func (rs *RateSchedule) bandsFor(vehicleType entities.VehicleType, t time.Time) []TimeBand {
+	if holidayBands, ok := rs.HolidayBands[vehicleType]; ok && rs.isHoliday(t) {
+		return holidayBands
+	}
+
+	bands := make([]TimeBand, 0, len(rs.Bands[vehicleType]))
+	for _, band := range rs.Bands[vehicleType] {
+		if band.appliesOn(t.Weekday()) {
+			bands = append(bands, band)
+		}
+	}
+	return bands
+}
+
+// isHoliday reports whether t falls on one of the schedule's holidays
+func (rs *RateSchedule) isHoliday(t time.Time) bool {
+	year, month, day := t.Date()
+	for _, holiday := range rs.Holidays {
+		hy, hm, hd := holiday.Date()
+		if hy == year && hm == month && hd == day {
+			return true
+		}
+	}
+	return false
+}
+
+// bandAt returns the band containing the offset from midnight; Validate rules out more than one
+func bandAt(bands []TimeBand, offset time.Duration) (TimeBand, bool) {
+	for _, band := range bands {
+		if band.Start <= offset && offset < band.End {
+			return band, true
+		}
+	}
+	return TimeBand{}, false
+}
+
+// nextBandStart returns the earliest band start after the offset from midnight
+func nextBandStart(bands []TimeBand, offset time.Duration) (time.Duration, bool) {
+	found := false
+	var earliest time.Duration
+	for _, band := range bands {
+		if band.Start > offset && (!found || band.Start < earliest) {
+			earliest, found = band.Start, true
+		}
+	}
+	return earliest, found
+}
+
+// wallClock returns the instant at the given offset from midnight on a date, by wall-clock time
+func wallClock(year int, month time.Month, day int, offset time.Duration, location *time.Location) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, int(offset), location)
+}
@@ -0,0 +1,122 @@
+package service
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"parkinglot/entities"
+)
+
+func TestRateSchedulePriceAcrossDSTChange(t *testing.T) {
+	santiago, err := time.LoadLocation("America/Santiago")
+	if err != nil {
+		t.Skipf("time zone data unavailable: %v", err)
+	}
+	schedule := &RateSchedule{
+		VersionID: "tariff-2026",
+		Location:  santiago,
+		Bands: map[entities.VehicleType][]TimeBand{
+			entities.CAR: {{Start: 0, End: 23*time.Hour + 45*time.Minute, Rate: 3600}},
+		},
+	}
+
+	// Clocks go back from midnight to 23:00 on 2026-04-04, so 23:30 happens twice. The band's end at
+	// 23:45 resolves to its first occurrence, which is already past during the second 23:30
+	secondPass := time.Date(2026, 4, 5, 3, 30, 0, 0, time.UTC) // 23:30 -04
+	ticket := &entities.Ticket{EntryTime: secondPass, PricePerHour: 3600, VehicleType: entities.CAR}
+	if got := schedule.Price(ticket, secondPass.Add(10*time.Minute)); got != 600 {
+		t.Errorf("Price of a 10 minute stay = %d, want 600", got)
+	}
+}
+
+func TestRateScheduleValidate(t *testing.T) {
+	weekdays := []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday}
+	weekend := []time.Weekday{time.Saturday, time.Sunday}
+	tests := []struct {
+		name     string
+		bands    []TimeBand
+		holidays []TimeBand
+		wantErr  bool
+	}{
+		{"adjacent bands", []TimeBand{{Start: 0, End: 7 * time.Hour, Rate: 100}, {Start: 7 * time.Hour, End: 24 * time.Hour, Rate: 300}}, nil, false},
+		{"same hours on different days", []TimeBand{{Days: weekdays, Start: 7 * time.Hour, End: 19 * time.Hour, Rate: 450}, {Days: weekend, Start: 7 * time.Hour, End: 19 * time.Hour, Rate: 250}}, nil, false},
+		{"inverted band", []TimeBand{{Start: 19 * time.Hour, End: 7 * time.Hour, Rate: 100}}, nil, true},
+		{"past midnight", []TimeBand{{Start: 22 * time.Hour, End: 25 * time.Hour, Rate: 100}}, nil, true},
+		{"negative rate", []TimeBand{{Start: 0, End: time.Hour, Rate: -1}}, nil, true},
+		{"overlap on every day", []TimeBand{{Start: 7 * time.Hour, End: 19 * time.Hour, Rate: 450}, {Start: 18 * time.Hour, End: 22 * time.Hour, Rate: 300}}, nil, true},
+		{"overlap on a shared day", []TimeBand{{Days: weekdays, Start: 7 * time.Hour, End: 19 * time.Hour, Rate: 450}, {Days: []time.Weekday{time.Friday}, Start: 17 * time.Hour, End: 23 * time.Hour, Rate: 500}}, nil, true},
+		{"holiday bands overlap whatever their days", nil, []TimeBand{{Days: weekdays, Start: 0, End: 12 * time.Hour, Rate: 100}, {Days: weekend, Start: 11 * time.Hour, End: 24 * time.Hour, Rate: 100}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schedule := &RateSchedule{VersionID: "tariff-2026", Bands: map[entities.VehicleType][]TimeBand{}, HolidayBands: map[entities.VehicleType][]TimeBand{}}
+			if tt.bands != nil {
+				schedule.Bands[entities.CAR] = tt.bands
+			}
+			if tt.holidays != nil {
+				schedule.HolidayBands[entities.CAR] = tt.holidays
+			}
+			err := schedule.Validate()
+			if tt.wantErr != (err != nil) {
+				t.Fatalf("Validate = %v, want error %v", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, ErrInvalidRateSchedule) {
+				t.Errorf("Validate = %v, want ErrInvalidRateSchedule", err)
+			}
+		})
+	}
+}
+
+func TestInvalidRateScheduleRejectedOnRegistration(t *testing.T) {
+	overlapping := &RateSchedule{
+		VersionID: "tariff-2026",
+		Bands: map[entities.VehicleType][]TimeBand{
+			entities.CAR: {{Start: 7 * time.Hour, End: 19 * time.Hour, Rate: 450}, {Start: 18 * time.Hour, End: 22 * time.Hour, Rate: 300}},
+		},
+	}
+
+	pls := NewParkingLotService([][3]int{{2, 0, 0}}, nil)
+	if err := pls.SetPricingPolicy(overlapping); !errors.Is(err, ErrInvalidRateSchedule) {
+		t.Errorf("SetPricingPolicy = %v, want ErrInvalidRateSchedule", err)
+	}
+	ticket, err := pls.ParkVehicle(entities.NewCar("CAR-1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ticket.PolicyVersion != NewHourlyPolicy().Version() {
+		t.Errorf("ticket issued under %q after the schedule was rejected", ticket.PolicyVersion)
+	}
+
+	defer func() {
+		if recovered := recover(); recovered == nil {
+			t.Error("WithPricingPolicy accepted a schedule with overlapping bands")
+		}
+	}()
+	NewParkingLotService([][3]int{{2, 0, 0}}, nil, WithPricingPolicy(overlapping))
+}
+
+func TestLotConfigRejectsOverlappingBands(t *testing.T) {
+	config, err := ParseLotConfig([]byte(`{
+		"floors": [{"spots": [{"kind": "car", "capacity": 2}]}],
+		"pricing": {"schedule": {"version": "tariff-2026", "bands": {"car": [
+			{"days": ["mon", "tue"], "start": "07:00", "end": "19:00", "rate": 450},
+			{"days": ["tue"], "start": "18:00", "end": "22:00", "rate": 300},
+			{"days": ["sat"], "start": "07:00", "end": "19:00", "rate": 250}
+		]}}}
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = NewParkingLotServiceFromConfig(config)
+	if !errors.Is(err, ErrInvalidConfig) {
+		t.Fatalf("NewParkingLotServiceFromConfig = %v, want ErrInvalidConfig", err)
+	}
+	if want := "pricing.schedule.bands.car[1]: overlaps pricing.schedule.bands.car[0]"; !strings.Contains(err.Error(), want) {
+		t.Errorf("problems do not include %q:\n%v", want, err)
+	}
+	if strings.Contains(err.Error(), "car[2]") {
+		t.Errorf("a band on other days reported as overlapping:\n%v", err)
+	}
+}
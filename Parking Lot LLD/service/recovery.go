@@ -0,0 +1,200 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"parkinglot/entities"
+)
+
+// WithJournal writes every park, payment, unpark, lost ticket, reservation change, spot state
+// change and floor closure to a write-ahead journal before it is acknowledged
+// Spot attributes set with ConfigureSpots are not journaled; they are kept by snapshots, so
+// compact after changing them to keep them across a crash
+func WithJournal(journal *Journal) Option {
+	return func(pls *ParkingLotService) {
+		pls.journal = journal
+	}
+}
+
+// writeAhead appends a record to the journal, if there is one, and returns its sequence number
+// Must be called with the lock held, before the change it describes is made
+func (pls *ParkingLotService) writeAhead(record JournalRecord) (uint64, error) {
+	if pls.journal == nil {
+		return 0, nil
+	}
+	if err := pls.journal.Append(&record); err != nil {
+		return 0, fmt.Errorf("%w: %v", ErrJournal, err)
+	}
+	pls.journalSeq = record.Seq
+	return record.Seq, nil
+}
+
+// abortJournaled records that the change journaled as seq failed and was undone, so replay skips it
+// It returns cause, joined with the failure to journal the abort if there was one
+// Must be called with the lock held
+func (pls *ParkingLotService) abortJournaled(seq uint64, cause error) error {
+	if seq == 0 {
+		return cause
+	}
+	if _, err := pls.writeAhead(JournalRecord{Type: JournalAbort, Aborts: seq}); err != nil {
+		return errors.Join(cause, err)
+	}
+	return cause
+}
+
+// Recover replays the journal onto the lot and returns how many records were applied
+// Records already covered by the snapshot the lot was restored from are skipped, as are records
+// whose change failed and was aborted
+// Call it once at startup, before the lot takes any traffic; no events are published for replayed records
+func (pls *ParkingLotService) Recover() (int, error) {
+	pls.mu.Lock()
+	defer pls.mu.Unlock()
+
+	if pls.journal == nil {
+		return 0, nil
+	}
+
+	records := pls.journal.Records()
+	aborted := make(map[uint64]bool)
+	for _, record := range records {
+		if record.Type == JournalAbort {
+			aborted[record.Aborts] = true
+		}
+	}
+
+	applied := 0
+	defer pls.resetAvailability()
+	for _, record := range records {
+		if record.Seq <= pls.journalSeq {
+			continue
+		}
+		if record.Type != JournalCheckpoint && record.Type != JournalAbort && !aborted[record.Seq] {
+			if err := pls.replay(record); err != nil {
+				return applied, fmt.Errorf("replay journal record %d (%s): %w", record.Seq, record.Type, err)
+			}
+			applied++
+		}
+		pls.journalSeq = record.Seq
+	}
+	return applied, nil
+}
+
+// replay applies one journal record
+// Must be called with the lock held
+func (pls *ParkingLotService) replay(record JournalRecord) error {
+	switch {
+	case record.Type == JournalPark && record.Park != nil:
+		ticket, err := record.Park.ticket()
+		if err != nil {
+			return err
+		}
+		// A durable ticket repository may hold later versions of this vehicle's tickets, saved
+		// before the crash; the journal is replayed over them in order
+		return pls.admitTicket(ticket)
+
+	case record.Type == JournalPay && record.Pay != nil:
+		ticket, err := pls.tickets.Get(record.Pay.Payment.TicketID)
+		if err != nil {
+			return err
+		}
+		payment := entities.Payment(record.Pay.Payment)
+		pls.payments[ticket.ID] = append(pls.payments[ticket.ID], &payment)
+		ticket.PaidAmount = record.Pay.PaidAmount
+		ticket.PaidAt = record.Pay.PaidAt
+		return pls.tickets.Save(ticket)
+
+	case record.Type == JournalUnpark && record.Unpark != nil:
+		ticket, err := pls.tickets.Get(record.Unpark.TicketID)
+		if err != nil {
+			return err
+		}
+		if !ticket.IsActive() {
+			return ErrVehicleNotParked
+		}
+		return pls.checkOut(ticket, *record.Unpark)
+
+	case record.Type == JournalLostTicket && record.LostTicket != nil:
+		replacement, err := record.LostTicket.Replacement.ticket()
+		if err != nil {
+			return err
+		}
+		lost, err := pls.tickets.Get(replacement.ReplacesID)
+		if err != nil {
+			return err
+		}
+		if !lost.IsActive() {
+			return ErrVehicleNotParked
+		}
+		lost.VoidedAt = record.LostTicket.VoidedAt
+		lost.ReplacedByID = replacement.ID
+		if err := pls.storeReplacement(lost, replacement); err != nil {
+			return err
+		}
+		pls.audit(record.LostTicket.Audit)
+		return nil
+
+	case record.Type == JournalReservation && record.Reservation != nil:
+		return pls.setReservation(record.Reservation.reservation())
+
+	case record.Type == JournalSpotState && record.SpotState != nil:
+		return pls.setSpotState(*record.SpotState)
+
+	case record.Type == JournalFloor && record.Floor != nil:
+		return pls.setFloorClosed(*record.Floor)
+	}
+	return fmt.Errorf("malformed %s record", record.Type)
+}
+
+// Compact saves a snapshot of the lot and empties the journal, which the snapshot now covers
+// A crash between the two steps is safe, since replay skips records the snapshot already holds
+func (pls *ParkingLotService) Compact(snapshotPath string) error {
+	pls.mu.Lock()
+	defer pls.mu.Unlock()
+
+	snapshot, err := pls.snapshot()
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(snapshot, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode snapshot: %w", err)
+	}
+	if err := writeFileAtomic(snapshotPath, data); err != nil {
+		return err
+	}
+	if pls.journal == nil {
+		return nil
+	}
+	return pls.journal.Reset()
+}
+
+// StartCompaction compacts the lot every interval until the returned stop function is called
+// Failures are passed to onError, which may be nil; the next tick tries again
+func (pls *ParkingLotService) StartCompaction(snapshotPath string, interval time.Duration, onError func(error)) (stop func()) {
+	ticker := time.NewTicker(interval)
+	done := make(chan struct{})
+	go func() {
+		for {
+			select {
+			case <-ticker.C:
+				if err := pls.Compact(snapshotPath); err != nil && onError != nil {
+					onError(err)
+				}
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			ticker.Stop()
+			close(done)
+		})
+	}
+}
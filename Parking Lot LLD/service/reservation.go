@@ -0,0 +1,361 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"parkinglot/entities"
+)
+
+var (
+	ErrReservationNotFound      = errors.New("reservation not found")
+	ErrReservationExists        = errors.New("vehicle already has an open reservation")
+	ErrReservationClosed        = errors.New("reservation is no longer open")
+	ErrReservationNotStarted    = errors.New("reservation window has not started")
+	ErrInvalidReservationWindow = errors.New("reservation window must end after it starts and in the future")
+)
+
+// ReservationRequest describes the spots a driver wants to book
+type ReservationRequest struct {
+	NumberPlate  string
+	VehicleType  entities.VehicleType
+	Spots        int                       // Adjacent spots needed; 0 uses the vehicle type's registered count
+	Requirements entities.SpotRequirements // Merged with the ones registered for the vehicle type
+	Start        time.Time
+	End          time.Time
+}
+
+// WithReservationGrace sets how long a held spot waits for a late arrival before the
+// reservation is released as a no-show; defaults to 15 minutes
+func WithReservationGrace(grace time.Duration) Option {
+	return func(pls *ParkingLotService) {
+		pls.reservationGrace = grace
+	}
+}
+
+// ReserveSpot books spots matching the request for its time window
+// Spots are picked from the lowest floor up among those vacant now and not booked by an overlapping
+// reservation. The spots stay open to walk-ins until the window starts; from then on they are held,
+// and if a walk-in is still parked there the reservation is moved to other matching spots
+func (pls *ParkingLotService) ReserveSpot(req ReservationRequest) (*entities.Reservation, error) {
+	info, ok := entities.LookupVehicleType(req.VehicleType)
+	if req.NumberPlate == "" || !ok {
+		return nil, ErrInvalidVehicle
+	}
+
+	defer pls.publishEvents()
+	pls.mu.Lock()
+	defer pls.mu.Unlock()
+
+	pls.sweepReservations()
+
+	now := pls.clock.Now()
+	if !req.End.After(req.Start) || !req.End.After(now) {
+		return nil, ErrInvalidReservationWindow
+	}
+	for _, existing := range pls.reservations {
+		if existing.IsOpen() && existing.NumberPlate == req.NumberPlate {
+			return nil, fmt.Errorf("%w: %s", ErrReservationExists, existing.ID)
+		}
+	}
+
+	spots := req.Spots
+	if spots < 1 {
+		spots = info.SpotsRequired
+	}
+	if spots < 1 {
+		spots = 1
+	}
+
+	reservation := entities.NewReservation(req.NumberPlate, req.VehicleType, spots,
+		info.Requirements.Merge(req.Requirements), req.Start, req.End, now)
+	floor, spotType, ids, err := pls.findReservableSpots(reservation)
+	if err != nil {
+		return nil, err
+	}
+	reservation.FloorID, reservation.SpotType, reservation.SpotIDs = floor.ID, spotType, ids
+	if !now.Before(reservation.Start) {
+		reservation.Status = entities.ReservationHolding
+	}
+	if err := pls.updateReservation(reservation); err != nil {
+		return nil, err
+	}
+	return reservation, nil
+}
+
+// ConfirmArrival parks the vehicle of a reservation in its held spots and issues a ticket
+// The reservation is looked up by code first and then by number plate
+func (pls *ParkingLotService) ConfirmArrival(codeOrPlate string) (*entities.Ticket, error) {
+	defer pls.publishEvents()
+	pls.mu.Lock()
+	defer pls.mu.Unlock()
+
+	pls.sweepReservations()
+
+	reservation := pls.findOpenReservation(codeOrPlate)
+	if reservation == nil {
+		return nil, ErrReservationNotFound
+	}
+	if pls.clock.Now().Before(reservation.Start) {
+		return nil, ErrReservationNotStarted
+	}
+	if err := pls.checkNotParked(reservation.NumberPlate); err != nil {
+		return nil, err
+	}
+
+	// The hold may have failed earlier if every matching spot was taken; try once more
+	if reservation.Status == entities.ReservationBooked && !pls.holdReservation(reservation) {
+		return nil, ErrParkingLotFull
+	}
+
+	vehicle, err := entities.NewVehicleOfType(reservation.VehicleType, reservation.NumberPlate, reservation.Spots)
+	if err != nil {
+		return nil, err
+	}
+
+	floor := pls.floors[reservation.FloorID-1]
+	return pls.issueTicket(vehicle, floor, reservation.SpotType, reservation.SpotIDs, "", reservation)
+}
+
+// CancelReservation cancels an open reservation, releasing its spots if they are held
+func (pls *ParkingLotService) CancelReservation(reservationID string) error {
+	defer pls.publishEvents()
+	pls.mu.Lock()
+	defer pls.mu.Unlock()
+
+	reservation, exists := pls.reservations[reservationID]
+	if !exists {
+		return ErrReservationNotFound
+	}
+	if !reservation.IsOpen() {
+		return ErrReservationClosed
+	}
+
+	return pls.releaseReservation(reservation, entities.ReservationCancelled)
+}
+
+// GetReservation retrieves a reservation by ID
+func (pls *ParkingLotService) GetReservation(reservationID string) (*entities.Reservation, error) {
+	pls.mu.RLock()
+	defer pls.mu.RUnlock()
+
+	reservation, exists := pls.reservations[reservationID]
+	if !exists {
+		return nil, ErrReservationNotFound
+	}
+	return reservation, nil
+}
+
+// ProcessReservations holds spots for reservations whose window has started and releases no-shows
+// Parking and reservation calls do this on their own; a scheduler can call it to keep status
+// counts current while the lot is quiet
+func (pls *ParkingLotService) ProcessReservations() {
+	defer pls.publishEvents()
+	pls.mu.Lock()
+	defer pls.mu.Unlock()
+
+	pls.sweepReservations()
+}
+
+// sweepReservations moves open reservations along as their windows start and their grace periods end
+// A change that can't be journaled is left for the next sweep
+// Must be called with the lock held
+func (pls *ParkingLotService) sweepReservations() {
+	now := pls.clock.Now()
+	for _, reservation := range pls.openReservations() {
+		if now.Before(reservation.Start) {
+			continue
+		}
+		if now.After(reservation.Start.Add(pls.reservationGrace)) {
+			pls.releaseReservation(reservation, entities.ReservationNoShow)
+			continue
+		}
+		if reservation.Status == entities.ReservationBooked {
+			pls.holdReservation(reservation)
+		}
+	}
+}
+
+// holdReservation takes the reservation's spots out of the vacant pool
+// If a walk-in is parked there, the reservation moves to other matching spots; it stays booked
+// and is retried on the next sweep when none are free
+// Must be called with the lock held
+func (pls *ParkingLotService) holdReservation(reservation *entities.Reservation) bool {
+	held := *reservation
+	if !pls.spotsVacant(reservation.FloorID, reservation.SpotType, reservation.SpotIDs) {
+		floor, spotType, ids, err := pls.findReservableSpots(reservation)
+		if err != nil {
+			return false
+		}
+		held.FloorID, held.SpotType, held.SpotIDs = floor.ID, spotType, ids
+	}
+	held.Status = entities.ReservationHolding
+	return pls.updateReservation(&held) == nil
+}
+
+// releaseReservation closes a reservation, returning held spots to the vacant pool
+// Must be called with the lock held
+func (pls *ParkingLotService) releaseReservation(reservation *entities.Reservation, status entities.ReservationStatus) error {
+	released := *reservation
+	released.Status = status
+	return pls.updateReservation(&released)
+}
+
+// updateReservation journals a reservation's new version and applies it
+// Must be called with the lock held
+func (pls *ParkingLotService) updateReservation(updated *entities.Reservation) error {
+	saved := reservationSnapshot(updated)
+	seq, err := pls.writeAhead(JournalRecord{Type: JournalReservation, Reservation: &saved})
+	if err != nil {
+		return err
+	}
+	if err := pls.setReservation(updated); err != nil {
+		return pls.abortJournaled(seq, err)
+	}
+	return nil
+}
+
+// setReservation stores a new or updated reservation, moving its hold on spots to match
+// An existing reservation is updated in place, so pointers to it stay current
+// Must be called with the lock held
+func (pls *ParkingLotService) setReservation(updated *entities.Reservation) error {
+	current := pls.reservations[updated.ID]
+
+	var released entities.ParkingSpot
+	if current != nil && current.Status == entities.ReservationHolding {
+		spots, err := pls.spotsOn(current.FloorID, current.SpotType)
+		if err == nil {
+			err = spots.UnreserveSpots(current.SpotIDs)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to release reservation hold: %w", err)
+		}
+		released = spots
+	}
+	if updated.Status == entities.ReservationHolding {
+		spots, err := pls.spotsOn(updated.FloorID, updated.SpotType)
+		if err == nil {
+			err = spots.ReserveSpots(updated.SpotIDs)
+		}
+		if err != nil {
+			err = fmt.Errorf("failed to hold spots: %w", err)
+			if released != nil {
+				if restoreErr := released.ReserveSpots(current.SpotIDs); restoreErr != nil {
+					err = errors.Join(err, fmt.Errorf("failed to restore reservation hold: %w", restoreErr))
+				}
+			}
+			return err
+		}
+	}
+
+	if released != nil && !updated.IsOpen() {
+		pls.emit(SpotReleased{At: pls.clock.Now(), FloorID: current.FloorID, SpotType: current.SpotType, SpotIDs: current.SpotIDs})
+	}
+	if current == nil {
+		pls.reservations[updated.ID] = updated
+	} else {
+		*current = *updated
+	}
+	return nil
+}
+
+// spotsVacant reports whether every one of the spots is Available
+// Must be called with the lock held
+func (pls *ParkingLotService) spotsVacant(floorID int, spotType entities.VehicleType, ids []int) bool {
+	spots, err := pls.spotsOn(floorID, spotType)
+	if err != nil {
+		return false
+	}
+	for _, id := range ids {
+		if spots.State(id) != entities.SpotAvailable {
+			return false
+		}
+	}
+	return true
+}
+
+// findReservableSpots finds vacant spots for a reservation that no other open reservation
+// has booked for an overlapping window, preferring spots with the preferred attributes
+// Must be called with the lock held
+func (pls *ParkingLotService) findReservableSpots(reservation *entities.Reservation) (*entities.ParkingSpace, entities.VehicleType, []int, error) {
+	passes := []entities.SpotRequirements{reservation.Requirements}
+	if reservation.Requirements.Prefer != 0 {
+		passes = []entities.SpotRequirements{reservation.Requirements.Preferred(), reservation.Requirements}
+	}
+
+	for _, pass := range passes {
+		for _, spotType := range pls.compatibility.SpotKinds(reservation.VehicleType) {
+			for _, floor := range pls.floors {
+				spots := floor.GetSpotByVehicleType(spotType)
+				if spots == nil {
+					continue
+				}
+				for after := 0; ; {
+					first, err := spots.FindMatchingRunAfter(after, reservation.Spots, pass)
+					if err != nil {
+						break
+					}
+					ids := spotIDs(first, reservation.Spots)
+					if !pls.reservationConflict(reservation, floor.ID, spotType, ids) {
+						return floor, spotType, ids, nil
+					}
+					after = first
+				}
+			}
+		}
+	}
+	return nil, 0, nil, ErrParkingLotFull
+}
+
+// reservationConflict reports whether another open reservation has booked any of the spots
+// for a window overlapping the reservation's
+func (pls *ParkingLotService) reservationConflict(reservation *entities.Reservation, floorID int, spotType entities.VehicleType, ids []int) bool {
+	for _, other := range pls.reservations {
+		if other == reservation || !other.IsOpen() || !other.Overlaps(reservation.Start, reservation.End) {
+			continue
+		}
+		for _, id := range ids {
+			if other.Holds(floorID, spotType, id) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// findOpenReservation returns the open reservation with the given code or, failing that, number plate
+func (pls *ParkingLotService) findOpenReservation(codeOrPlate string) *entities.Reservation {
+	var byPlate *entities.Reservation
+	for _, reservation := range pls.reservations {
+		if !reservation.IsOpen() {
+			continue
+		}
+		if reservation.Code == codeOrPlate {
+			return reservation
+		}
+		if reservation.NumberPlate == codeOrPlate {
+			byPlate = reservation
+		}
+	}
+	return byPlate
+}
+
+// openReservations returns the open reservations ordered by window start, so earlier
+// bookings get first pick when spots have to be moved
+func (pls *ParkingLotService) openReservations() []*entities.Reservation {
+	open := make([]*entities.Reservation, 0, len(pls.reservations))
+	for _, reservation := range pls.reservations {
+		if reservation.IsOpen() {
+			open = append(open, reservation)
+		}
+	}
+	sort.Slice(open, func(i, j int) bool {
+		if open[i].Start.Equal(open[j].Start) {
+			return open[i].ID < open[j].ID
+		}
+		return open[i].Start.Before(open[j].Start)
+	})
+	return open
+}
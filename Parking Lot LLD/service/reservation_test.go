@@ -0,0 +1,154 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"parkinglot/entities"
+)
+
+// newReservationLot creates a one-floor lot with two car spots and a reservation for BOOKED-1
+// whose two hour window starts after startIn
+func newReservationLot(t *testing.T, startIn time.Duration) (*ParkingLotService, *entities.ManualClock, *entities.Reservation) {
+	t.Helper()
+	clock := entities.NewManualClock(time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC))
+	pls := NewParkingLotService([][3]int{{2, 0, 0}}, nil, WithClock(clock), WithReservationGrace(15*time.Minute))
+	reservation, err := pls.ReserveSpot(ReservationRequest{
+		NumberPlate: "BOOKED-1",
+		VehicleType: entities.CAR,
+		Start:       clock.Now().Add(startIn),
+		End:         clock.Now().Add(startIn + 2*time.Hour),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pls, clock, reservation
+}
+
+// carCounts returns the reserved and vacant car spots on the first floor
+func carCounts(pls *ParkingLotService) (reserved, vacant int) {
+	cars := pls.GetParkingLotStatus().Floors[0].Spots[entities.CAR]
+	return cars.Reserved, cars.Vacant
+}
+
+func TestConfirmArrival(t *testing.T) {
+	for _, by := range []string{"code", "plate"} {
+		t.Run(by, func(t *testing.T) {
+			pls, _, reservation := newReservationLot(t, 0)
+			key := reservation.Code
+			if by == "plate" {
+				key = reservation.NumberPlate
+			}
+
+			ticket, err := pls.ConfirmArrival(key)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if ticket.ReservationID != reservation.ID || ticket.SpotIDs[0] != reservation.SpotIDs[0] {
+				t.Errorf("ticket for reservation %q in spots %v, want %s in %v",
+					ticket.ReservationID, ticket.SpotIDs, reservation.ID, reservation.SpotIDs)
+			}
+			if got, _ := pls.GetReservation(reservation.ID); got.Status != entities.ReservationFulfilled || got.TicketID != ticket.ID {
+				t.Errorf("reservation is %s with ticket %q, want fulfilled with %s", got.Status, got.TicketID, ticket.ID)
+			}
+			if reserved, vacant := carCounts(pls); reserved != 0 || vacant != 1 {
+				t.Errorf("%d spots reserved and %d vacant after arrival, want 0 and 1", reserved, vacant)
+			}
+			if _, err := pls.ConfirmArrival(key); !errors.Is(err, ErrReservationNotFound) {
+				t.Errorf("confirming twice: got %v, want ErrReservationNotFound", err)
+			}
+		})
+	}
+}
+
+func TestCancelReservation(t *testing.T) {
+	pls, _, reservation := newReservationLot(t, 0)
+	if reserved, _ := carCounts(pls); reserved != 1 {
+		t.Fatalf("%d spots held for a reservation whose window has started, want 1", reserved)
+	}
+
+	if err := pls.CancelReservation(reservation.ID); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := pls.GetReservation(reservation.ID); got.Status != entities.ReservationCancelled {
+		t.Errorf("reservation is %s, want cancelled", got.Status)
+	}
+	if reserved, vacant := carCounts(pls); reserved != 0 || vacant != 2 {
+		t.Errorf("%d spots reserved and %d vacant after cancelling, want 0 and 2", reserved, vacant)
+	}
+	if err := pls.CancelReservation(reservation.ID); !errors.Is(err, ErrReservationClosed) {
+		t.Errorf("cancelling twice: got %v, want ErrReservationClosed", err)
+	}
+	if err := pls.CancelReservation("RES-missing"); !errors.Is(err, ErrReservationNotFound) {
+		t.Errorf("cancelling an unknown reservation: got %v, want ErrReservationNotFound", err)
+	}
+	if _, err := pls.ConfirmArrival(reservation.Code); !errors.Is(err, ErrReservationNotFound) {
+		t.Errorf("arriving for a cancelled reservation: got %v, want ErrReservationNotFound", err)
+	}
+}
+
+func TestReservationHeldWhenWindowStarts(t *testing.T) {
+	pls, clock, reservation := newReservationLot(t, time.Hour)
+	booked := reservation.SpotIDs[0]
+
+	// Until the window starts the spot is open to walk-ins, so one takes it
+	if reserved, vacant := carCounts(pls); reserved != 0 || vacant != 2 {
+		t.Fatalf("%d spots reserved and %d vacant before the window, want 0 and 2", reserved, vacant)
+	}
+	if _, err := pls.ConfirmArrival(reservation.Code); !errors.Is(err, ErrReservationNotStarted) {
+		t.Errorf("arriving early: got %v, want ErrReservationNotStarted", err)
+	}
+	walkIn, err := pls.ParkVehicle(entities.NewCar("WALK-IN"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if walkIn.SpotIDs[0] != booked {
+		t.Fatalf("walk-in parked in spot %d, want the booked spot %d", walkIn.SpotIDs[0], booked)
+	}
+
+	// Once the window starts the sweep holds the other spot instead
+	clock.Advance(time.Hour)
+	pls.ProcessReservations()
+	got, _ := pls.GetReservation(reservation.ID)
+	if got.Status != entities.ReservationHolding || got.SpotIDs[0] == booked {
+		t.Errorf("reservation is %s in spots %v, want holding a spot other than %d", got.Status, got.SpotIDs, booked)
+	}
+	if reserved, vacant := carCounts(pls); reserved != 1 || vacant != 0 {
+		t.Errorf("%d spots reserved and %d vacant once held, want 1 and 0", reserved, vacant)
+	}
+	if _, err := pls.ParkVehicle(entities.NewCar("WALK-IN-2")); !errors.Is(err, ErrParkingLotFull) {
+		t.Errorf("parking in a held spot: got %v, want ErrParkingLotFull", err)
+	}
+
+	ticket, err := pls.ConfirmArrival("BOOKED-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ticket.SpotIDs[0] != got.SpotIDs[0] {
+		t.Errorf("arrival parked in spot %d, want the held spot %d", ticket.SpotIDs[0], got.SpotIDs[0])
+	}
+}
+
+func TestReservationNoShowReleased(t *testing.T) {
+	pls, clock, reservation := newReservationLot(t, 0)
+
+	// Still within the grace period the spot stays held
+	clock.Advance(15 * time.Minute)
+	pls.ProcessReservations()
+	if got, _ := pls.GetReservation(reservation.ID); got.Status != entities.ReservationHolding {
+		t.Fatalf("reservation is %s at the end of the grace period, want holding", got.Status)
+	}
+
+	clock.Advance(time.Minute)
+	pls.ProcessReservations()
+	if got, _ := pls.GetReservation(reservation.ID); got.Status != entities.ReservationNoShow {
+		t.Errorf("reservation is %s after the grace period, want no-show", got.Status)
+	}
+	if reserved, vacant := carCounts(pls); reserved != 0 || vacant != 2 {
+		t.Errorf("%d spots reserved and %d vacant after the no-show, want 0 and 2", reserved, vacant)
+	}
+	if _, err := pls.ConfirmArrival(reservation.Code); !errors.Is(err, ErrReservationNotFound) {
+		t.Errorf("arriving after the no-show: got %v, want ErrReservationNotFound", err)
+	}
+}
@@ -0,0 +1,562 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"parkinglot/entities"
+)
+
+// SnapshotVersion is the format version written by Snapshot
+// Bump it whenever a change to the format would make older readers restore the lot wrongly
+const SnapshotVersion = 1
+
+var (
+	ErrUnsupportedSnapshot  = errors.New("unsupported snapshot version")
+	ErrInconsistentSnapshot = errors.New("snapshot is inconsistent")
+)
+
+// Snapshot is the full state of a parking lot at a moment, in a form that can be stored as JSON
+// Vehicle types, spot attributes and spot states are stored by name
+// Configuration that lives in code, such as pricing policies, allocation strategy and payment
+// processors, is not part of the snapshot and is passed again as options when restoring
+type Snapshot struct {
+	Version       int                          `json:"version"`
+	TakenAt       time.Time                    `json:"taken_at"`
+	Pricing       map[entities.VehicleType]int `json:"pricing"`
+	PolicyVersion string                       `json:"policy_version"`
+	Floors        []FloorSnapshot              `json:"floors"`
+	Tickets       []TicketSnapshot             `json:"tickets"`
+	Payments      []PaymentSnapshot            `json:"payments,omitempty"`
+	Reservations  []ReservationSnapshot        `json:"reservations,omitempty"`
+	OfferedRates  map[entities.VehicleType]int `json:"offered_rates,omitempty"` // Last rate offered per spot kind
+	RateChanges   []RateChange                 `json:"rate_changes,omitempty"`
+	AuditLog      []AuditEntry                 `json:"audit_log,omitempty"`
+	JournalSeq    uint64                       `json:"journal_seq,omitempty"` // Last journal record the snapshot includes
+}
+
+// FloorSnapshot is the layout and spot states of one floor
+type FloorSnapshot struct {
+	ID     int                `json:"id"`
+	Name   string             `json:"name,omitempty"`
+	Closed bool               `json:"closed,omitempty"`
+	Spots  []SpotKindSnapshot `json:"spots"`
+}
+
+// SpotKindSnapshot is one spot collection of a floor
+// Only spots that differ from a new, vacant spot without attributes are listed
+type SpotKindSnapshot struct {
+	Kind     entities.VehicleType `json:"kind"`
+	Capacity int                  `json:"capacity"`
+	Spots    []SpotSnapshot       `json:"spots,omitempty"`
+}
+
+// SpotSnapshot is the state and features of one spot
+type SpotSnapshot struct {
+	ID         int                     `json:"id"`
+	State      entities.SpotState      `json:"state"`
+	Attributes entities.SpotAttributes `json:"attributes,omitempty"`
+	ChargerKW  float64                 `json:"charger_kw,omitempty"`
+	ClosedOut  bool                    `json:"closed_out,omitempty"` // Out of service only because the floor is closed
+}
+
+// TicketSnapshot is a ticket with its vehicle flattened to type, plate and spot count
+type TicketSnapshot struct {
+	ID             string               `json:"id"`
+	NumberPlate    string               `json:"number_plate"`
+	VehicleType    entities.VehicleType `json:"vehicle_type"`
+	VehicleSpots   int                  `json:"vehicle_spots"`
+	EntryTime      time.Time            `json:"entry_time"`
+	ExitTime       time.Time            `json:"exit_time"`
+	FloorID        int                  `json:"floor_id"`
+	SpotIDs        []int                `json:"spot_ids"`
+	SpotType       entities.VehicleType `json:"spot_type"`
+	Fallback       bool                 `json:"fallback,omitempty"`
+	PricePerHour   int                  `json:"price_per_hour"`
+	RateMultiplier float64              `json:"rate_multiplier"`
+	PolicyVersion  string               `json:"policy_version"`
+	Fee            int                  `json:"fee"`
+	PaidAmount     int                  `json:"paid_amount"`
+	PaidAt         time.Time            `json:"paid_at"`
+	Penalty        int                  `json:"penalty,omitempty"`
+	ReplacesID     string               `json:"replaces_id,omitempty"`
+	VoidedAt       time.Time            `json:"voided_at"`
+	ReplacedByID   string               `json:"replaced_by_id,omitempty"`
+	ReservationID  string               `json:"reservation_id,omitempty"`
+	EntryGateID    string               `json:"entry_gate_id,omitempty"`
+	ExitGateID     string               `json:"exit_gate_id,omitempty"`
+}
+
+// PaymentSnapshot is one payment attempt
+type PaymentSnapshot struct {
+	ID            string                 `json:"id"`
+	TicketID      string                 `json:"ticket_id"`
+	Amount        int                    `json:"amount"`
+	Method        entities.PaymentMethod `json:"method"`
+	Status        entities.PaymentStatus `json:"status"`
+	Reference     string                 `json:"reference,omitempty"`
+	FailureReason string                 `json:"failure_reason,omitempty"`
+	Attempt       int                    `json:"attempt"`
+	CreatedAt     time.Time              `json:"created_at"`
+}
+
+// ReservationSnapshot is one reservation
+type ReservationSnapshot struct {
+	ID           string                     `json:"id"`
+	Code         string                     `json:"code"`
+	NumberPlate  string                     `json:"number_plate"`
+	VehicleType  entities.VehicleType       `json:"vehicle_type"`
+	Spots        int                        `json:"spots"`
+	Require      entities.SpotAttributes    `json:"require,omitempty"`
+	Prefer       entities.SpotAttributes    `json:"prefer,omitempty"`
+	MinChargerKW float64                    `json:"min_charger_kw,omitempty"`
+	FloorID      int                        `json:"floor_id"`
+	SpotType     entities.VehicleType       `json:"spot_type"`
+	SpotIDs      []int                      `json:"spot_ids"`
+	Start        time.Time                  `json:"start"`
+	End          time.Time                  `json:"end"`
+	Status       entities.ReservationStatus `json:"status"`
+	TicketID     string                     `json:"ticket_id,omitempty"`
+	CreatedAt    time.Time                  `json:"created_at"`
+}
+
+// Snapshot captures the lot's current state
+func (pls *ParkingLotService) Snapshot() (*Snapshot, error) {
+	pls.mu.RLock()
+	defer pls.mu.RUnlock()
+
+	return pls.snapshot()
+}
+
+// snapshot captures the lot's current state
+// Must be called with the lock held
+func (pls *ParkingLotService) snapshot() (*Snapshot, error) {
+	tickets, err := pls.tickets.ListByTimeRange(time.Time{}, time.Time{})
+	if err != nil {
+		return nil, fmt.Errorf("list tickets: %w", err)
+	}
+
+	snapshot := &Snapshot{
+		Version:       SnapshotVersion,
+		TakenAt:       pls.clock.Now(),
+		Pricing:       make(map[entities.VehicleType]int, len(pls.pricing)),
+		PolicyVersion: pls.policy.Version(),
+		Floors:        make([]FloorSnapshot, len(pls.floors)),
+		Tickets:       make([]TicketSnapshot, 0, len(tickets)),
+		OfferedRates:  make(map[entities.VehicleType]int, len(pls.offeredRates)),
+		RateChanges:   append([]RateChange(nil), pls.rateChanges...),
+		AuditLog:      append([]AuditEntry(nil), pls.auditLog...),
+		JournalSeq:    pls.journalSeq,
+	}
+	for vehicleType, rate := range pls.pricing {
+		snapshot.Pricing[vehicleType] = rate
+	}
+	for spotType, rate := range pls.offeredRates {
+		snapshot.OfferedRates[spotType] = rate
+	}
+
+	for i, floor := range pls.floors {
+		snapshot.Floors[i] = floorSnapshot(floor)
+	}
+
+	for _, ticket := range tickets {
+		snapshot.Tickets = append(snapshot.Tickets, ticketSnapshot(ticket))
+	}
+
+	for _, ticket := range snapshot.Tickets {
+		for _, payment := range pls.payments[ticket.ID] {
+			snapshot.Payments = append(snapshot.Payments, PaymentSnapshot(*payment))
+		}
+	}
+
+	for _, reservation := range pls.reservations {
+		snapshot.Reservations = append(snapshot.Reservations, reservationSnapshot(reservation))
+	}
+	sort.Slice(snapshot.Reservations, func(i, j int) bool {
+		a, b := snapshot.Reservations[i], snapshot.Reservations[j]
+		if a.CreatedAt.Equal(b.CreatedAt) {
+			return a.ID < b.ID
+		}
+		return a.CreatedAt.Before(b.CreatedAt)
+	})
+
+	return snapshot, nil
+}
+
+// SaveSnapshot writes the lot's current state to a JSON file
+// The file is replaced atomically, so a crash mid-write leaves the previous snapshot intact
+func (pls *ParkingLotService) SaveSnapshot(path string) error {
+	snapshot, err := pls.Snapshot()
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(snapshot, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode snapshot: %w", err)
+	}
+	return writeFileAtomic(path, data)
+}
+
+// LoadSnapshot reads a snapshot written by SaveSnapshot
+func LoadSnapshot(path string) (*Snapshot, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var snapshot Snapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return nil, fmt.Errorf("decode snapshot %s: %w", path, err)
+	}
+	if snapshot.Version != SnapshotVersion {
+		return nil, fmt.Errorf("%w: %d", ErrUnsupportedSnapshot, snapshot.Version)
+	}
+	return &snapshot, nil
+}
+
+// RestoreParkingLotService rebuilds a lot from a snapshot
+// opts configure the restored lot as they would a new one; every pricing policy version the
+// snapshot's active tickets were issued under must be registered through them
+// The snapshot's tickets are saved to the ticket repository, replacing any copies it already holds
+// The restored lot's spot states, occupancy and tickets match the snapshot exactly, or an error is returned
+func RestoreParkingLotService(snapshot *Snapshot, opts ...Option) (*ParkingLotService, error) {
+	if snapshot == nil || snapshot.Version != SnapshotVersion {
+		version := 0
+		if snapshot != nil {
+			version = snapshot.Version
+		}
+		return nil, fmt.Errorf("%w: %d", ErrUnsupportedSnapshot, version)
+	}
+
+	capacities := make([]map[entities.VehicleType]int, len(snapshot.Floors))
+	for i, floor := range snapshot.Floors {
+		if floor.ID != i+1 {
+			return nil, fmt.Errorf("%w: floor %d listed at position %d", ErrInconsistentSnapshot, floor.ID, i+1)
+		}
+		capacities[i] = make(map[entities.VehicleType]int, len(floor.Spots))
+		for _, kind := range floor.Spots {
+			capacities[i][kind.Kind] = kind.Capacity
+		}
+	}
+
+	pls := NewParkingLotServiceWithCapacities(capacities, snapshot.Pricing, opts...)
+	policy, ok := pls.policies[snapshot.PolicyVersion]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownPricingPolicy, snapshot.PolicyVersion)
+	}
+	pls.policy = policy
+
+	for i, floor := range snapshot.Floors {
+		pls.floors[i].Name = floor.Name
+	}
+	if err := pls.restoreSpots(snapshot); err != nil {
+		return nil, err
+	}
+	if err := pls.restoreTickets(snapshot); err != nil {
+		return nil, err
+	}
+	if err := pls.restoreReservations(snapshot); err != nil {
+		return nil, err
+	}
+
+	// Close floors last, so that only the spots that were vacant go out of service with them
+	for i, floor := range snapshot.Floors {
+		if floor.Closed {
+			pls.floors[i].SetClosed(true)
+		}
+	}
+	if err := pls.verifySpots(snapshot); err != nil {
+		return nil, err
+	}
+
+	ticketIDs := make(map[string]bool, len(snapshot.Tickets))
+	for _, saved := range snapshot.Tickets {
+		ticketIDs[saved.ID] = true
+	}
+	for _, payment := range snapshot.Payments {
+		if !ticketIDs[payment.TicketID] {
+			return nil, fmt.Errorf("%w: payment %s for unknown ticket %s", ErrInconsistentSnapshot, payment.ID, payment.TicketID)
+		}
+		restored := entities.Payment(payment)
+		pls.payments[payment.TicketID] = append(pls.payments[payment.TicketID], &restored)
+	}
+	for spotType, rate := range snapshot.OfferedRates {
+		pls.offeredRates[spotType] = rate
+	}
+	pls.rateChanges = append(pls.rateChanges, snapshot.RateChanges...)
+	pls.auditLog = append(pls.auditLog, snapshot.AuditLog...)
+
+	// Journal records up to the snapshot's are already in it; new ones must be numbered after them
+	pls.journalSeq = snapshot.JournalSeq
+	if pls.journal != nil {
+		pls.journal.skipTo(snapshot.JournalSeq)
+	}
+
+	pls.resetAvailability()
+	return pls, nil
+}
+
+// restoreSpots applies spot attributes and the states set by operators
+func (pls *ParkingLotService) restoreSpots(snapshot *Snapshot) error {
+	for i, floor := range snapshot.Floors {
+		for _, kind := range floor.Spots {
+			spots := pls.floors[i].Spots[kind.Kind]
+			for _, spot := range kind.Spots {
+				if err := spots.SetSpotAttributes(spot.ID, spot.Attributes, spot.ChargerKW); err != nil {
+					return fmt.Errorf("%w: floor %d %s spot %d: %v", ErrInconsistentSnapshot, floor.ID, kind.Kind, spot.ID, err)
+				}
+				operatorState := spot.State == entities.SpotOutOfService || spot.State == entities.SpotBlocked
+				if operatorState && !spot.ClosedOut {
+					if err := spots.SetSpotsState([]int{spot.ID}, spot.State); err != nil {
+						return fmt.Errorf("%w: floor %d %s spot %d: %v", ErrInconsistentSnapshot, floor.ID, kind.Kind, spot.ID, err)
+					}
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// restoreTickets rebuilds every ticket and parks the vehicles of active ones
+func (pls *ParkingLotService) restoreTickets(snapshot *Snapshot) error {
+	parked := make(map[string]bool)
+	for _, saved := range snapshot.Tickets {
+		ticket, err := saved.ticket()
+		if err != nil {
+			return fmt.Errorf("%w: ticket %s: %v", ErrInconsistentSnapshot, saved.ID, err)
+		}
+		if err := pls.tickets.Save(ticket); err != nil {
+			return fmt.Errorf("failed to store ticket %s: %w", ticket.ID, err)
+		}
+		if !ticket.IsActive() {
+			continue
+		}
+
+		plate := ticket.Vehicle.GetNumberPlate()
+		if parked[plate] {
+			return fmt.Errorf("%w: vehicle %s has two active tickets", ErrInconsistentSnapshot, plate)
+		}
+		if _, ok := pls.policies[ticket.PolicyVersion]; !ok {
+			return fmt.Errorf("%w: %s", ErrUnknownPricingPolicy, ticket.PolicyVersion)
+		}
+		if ticket.FloorID < 1 || ticket.FloorID > len(pls.floors) {
+			return fmt.Errorf("%w: ticket %s on unknown floor %d", ErrInconsistentSnapshot, ticket.ID, ticket.FloorID)
+		}
+		spots := pls.floors[ticket.FloorID-1].GetSpotByVehicleType(ticket.SpotType)
+		if spots == nil {
+			return fmt.Errorf("%w: ticket %s on missing %s spots", ErrInconsistentSnapshot, ticket.ID, ticket.SpotType)
+		}
+		if err := spots.OccupySpots(ticket.SpotIDs, ticket.Vehicle); err != nil {
+			return fmt.Errorf("%w: ticket %s: %v", ErrInconsistentSnapshot, ticket.ID, err)
+		}
+		parked[plate] = true
+	}
+	return nil
+}
+
+// restoreReservations rebuilds every reservation and holds the spots of those holding them
+func (pls *ParkingLotService) restoreReservations(snapshot *Snapshot) error {
+	for _, saved := range snapshot.Reservations {
+		reservation := saved.reservation()
+		pls.reservations[reservation.ID] = reservation
+		if reservation.Status != entities.ReservationHolding {
+			continue
+		}
+
+		if reservation.FloorID < 1 || reservation.FloorID > len(pls.floors) {
+			return fmt.Errorf("%w: reservation %s on unknown floor %d", ErrInconsistentSnapshot, reservation.ID, reservation.FloorID)
+		}
+		spots := pls.floors[reservation.FloorID-1].GetSpotByVehicleType(reservation.SpotType)
+		if spots == nil {
+			return fmt.Errorf("%w: reservation %s on missing %s spots", ErrInconsistentSnapshot, reservation.ID, reservation.SpotType)
+		}
+		if err := spots.ReserveSpots(reservation.SpotIDs); err != nil {
+			return fmt.Errorf("%w: reservation %s: %v", ErrInconsistentSnapshot, reservation.ID, err)
+		}
+	}
+	return nil
+}
+
+// verifySpots checks that every spot ended up in the state the snapshot recorded
+func (pls *ParkingLotService) verifySpots(snapshot *Snapshot) error {
+	for i, floor := range snapshot.Floors {
+		for _, kind := range floor.Spots {
+			spots := pls.floors[i].Spots[kind.Kind]
+			expected := make(map[int]SpotSnapshot, len(kind.Spots))
+			for _, spot := range kind.Spots {
+				expected[spot.ID] = spot
+			}
+			for spotID := 1; spotID <= kind.Capacity; spotID++ {
+				want := expected[spotID] // Unlisted spots are Available
+				if got := spots.State(spotID); got != want.State || spots.ClosedOut(spotID) != want.ClosedOut {
+					return fmt.Errorf("%w: floor %d %s spot %d restored as %s, snapshot has %s",
+						ErrInconsistentSnapshot, floor.ID, kind.Kind, spotID, got, want.State)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// floorSnapshot captures a floor's spot collections in registry order
+func floorSnapshot(floor *entities.ParkingSpace) FloorSnapshot {
+	saved := FloorSnapshot{ID: floor.ID, Name: floor.Name, Closed: floor.Closed}
+	for _, spotType := range sortedSpotKinds(floor) {
+		spots := floor.Spots[spotType]
+		kind := SpotKindSnapshot{Kind: spotType, Capacity: spots.GetTotalSpots()}
+		for spotID := 1; spotID <= kind.Capacity; spotID++ {
+			spot, _ := spots.GetSpot(spotID)
+			closedOut := spots.ClosedOut(spotID)
+			if spot.State == entities.SpotAvailable && spot.Attributes == 0 && !closedOut {
+				continue
+			}
+			kind.Spots = append(kind.Spots, SpotSnapshot{
+				ID:         spotID,
+				State:      spot.State,
+				Attributes: spot.Attributes,
+				ChargerKW:  spot.ChargerKW,
+				ClosedOut:  closedOut,
+			})
+		}
+		saved.Spots = append(saved.Spots, kind)
+	}
+	return saved
+}
+
+func ticketSnapshot(ticket *entities.Ticket) TicketSnapshot {
+	return TicketSnapshot{
+		ID:             ticket.ID,
+		NumberPlate:    ticket.Vehicle.GetNumberPlate(),
+		VehicleType:    ticket.VehicleType,
+		VehicleSpots:   entities.SpotsRequired(ticket.Vehicle),
+		EntryTime:      ticket.EntryTime,
+		ExitTime:       ticket.ExitTime,
+		FloorID:        ticket.FloorID,
+		SpotIDs:        append([]int(nil), ticket.SpotIDs...),
+		SpotType:       ticket.SpotType,
+		Fallback:       ticket.Fallback,
+		PricePerHour:   ticket.PricePerHour,
+		RateMultiplier: ticket.RateMultiplier,
+		PolicyVersion:  ticket.PolicyVersion,
+		Fee:            ticket.Fee,
+		PaidAmount:     ticket.PaidAmount,
+		PaidAt:         ticket.PaidAt,
+		Penalty:        ticket.Penalty,
+		ReplacesID:     ticket.ReplacesID,
+		VoidedAt:       ticket.VoidedAt,
+		ReplacedByID:   ticket.ReplacedByID,
+		ReservationID:  ticket.ReservationID,
+		EntryGateID:    ticket.EntryGateID,
+		ExitGateID:     ticket.ExitGateID,
+	}
+}
+
+// ticket rebuilds the ticket and its vehicle
+func (saved TicketSnapshot) ticket() (*entities.Ticket, error) {
+	if len(saved.SpotIDs) == 0 {
+		return nil, errors.New("ticket holds no spots")
+	}
+	vehicle, err := entities.NewVehicleOfType(saved.VehicleType, saved.NumberPlate, saved.VehicleSpots)
+	if err != nil {
+		return nil, err
+	}
+	return &entities.Ticket{
+		ID:             saved.ID,
+		Vehicle:        vehicle,
+		EntryTime:      saved.EntryTime,
+		ExitTime:       saved.ExitTime,
+		FloorID:        saved.FloorID,
+		SpotID:         saved.SpotIDs[0],
+		SpotIDs:        append([]int(nil), saved.SpotIDs...),
+		SpotType:       saved.SpotType,
+		VehicleType:    saved.VehicleType,
+		Fallback:       saved.Fallback,
+		PricePerHour:   saved.PricePerHour,
+		RateMultiplier: saved.RateMultiplier,
+		PolicyVersion:  saved.PolicyVersion,
+		Fee:            saved.Fee,
+		PaidAmount:     saved.PaidAmount,
+		PaidAt:         saved.PaidAt,
+		Penalty:        saved.Penalty,
+		ReplacesID:     saved.ReplacesID,
+		VoidedAt:       saved.VoidedAt,
+		ReplacedByID:   saved.ReplacedByID,
+		ReservationID:  saved.ReservationID,
+		EntryGateID:    saved.EntryGateID,
+		ExitGateID:     saved.ExitGateID,
+	}, nil
+}
+
+func reservationSnapshot(reservation *entities.Reservation) ReservationSnapshot {
+	return ReservationSnapshot{
+		ID:           reservation.ID,
+		Code:         reservation.Code,
+		NumberPlate:  reservation.NumberPlate,
+		VehicleType:  reservation.VehicleType,
+		Spots:        reservation.Spots,
+		Require:      reservation.Requirements.Require,
+		Prefer:       reservation.Requirements.Prefer,
+		MinChargerKW: reservation.Requirements.MinChargerKW,
+		FloorID:      reservation.FloorID,
+		SpotType:     reservation.SpotType,
+		SpotIDs:      append([]int(nil), reservation.SpotIDs...),
+		Start:        reservation.Start,
+		End:          reservation.End,
+		Status:       reservation.Status,
+		TicketID:     reservation.TicketID,
+		CreatedAt:    reservation.CreatedAt,
+	}
+}
+
+func (saved ReservationSnapshot) reservation() *entities.Reservation {
+	return &entities.Reservation{
+		ID:          saved.ID,
+		Code:        saved.Code,
+		NumberPlate: saved.NumberPlate,
+		VehicleType: saved.VehicleType,
+		Spots:       saved.Spots,
+		Requirements: entities.SpotRequirements{
+			Require:      saved.Require,
+			Prefer:       saved.Prefer,
+			MinChargerKW: saved.MinChargerKW,
+		},
+		FloorID:   saved.FloorID,
+		SpotType:  saved.SpotType,
+		SpotIDs:   append([]int(nil), saved.SpotIDs...),
+		Start:     saved.Start,
+		End:       saved.End,
+		Status:    saved.Status,
+		TicketID:  saved.TicketID,
+		CreatedAt: saved.CreatedAt,
+	}
+}
+
+// writeFileAtomic writes data to a temporary file next to path, syncs it and renames it into place
+func writeFileAtomic(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name()) // No-op once renamed
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return err
+	}
+	return syncDir(filepath.Dir(path))
+}
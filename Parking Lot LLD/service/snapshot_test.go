@@ -0,0 +1,164 @@
+package service
+
+import (
+	"errors"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"parkinglot/entities"
+)
+
+// newSnapshotLot builds a two-floor lot holding every kind of state a snapshot records: an exited
+// ticket, active tickets over one and two spots, a held reservation, a spot taken out of service
+// and a closed floor with a vehicle still parked on it
+func newSnapshotLot(t *testing.T, clock entities.Clock) *ParkingLotService {
+	t.Helper()
+	pls := NewParkingLotService([][3]int{{3, 2, 3}, {3, 2, 1}}, nil, WithClock(clock))
+
+	exited, err := pls.ParkVehicle(entities.NewCar("EXITED-1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := pls.PayTicket(exited.ID, entities.PaymentCash, 0); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := pls.UnparkVehicle(exited.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, vehicle := range []entities.Vehicle{entities.NewCar("CAR-1"), entities.NewTruckWithTrailer("TRUCK-1", 2)} {
+		if _, err := pls.ParkVehicle(vehicle); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := pls.SetSpotState(1, entities.CAR, []int{3}, entities.SpotOutOfService, "ops"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := pls.ReserveSpot(ReservationRequest{
+		NumberPlate: "BOOKED-1",
+		VehicleType: entities.CAR,
+		Start:       clock.Now(),
+		End:         clock.Now().Add(2 * time.Hour),
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	// The first floor has no car spots left, so this one goes to the second floor before it closes
+	if _, err := pls.ParkVehicle(entities.NewCar("CAR-2")); err != nil {
+		t.Fatal(err)
+	}
+	if err := pls.CloseFloor(2, "ops"); err != nil {
+		t.Fatal(err)
+	}
+	return pls
+}
+
+func TestSnapshotRoundTrip(t *testing.T) {
+	clock := entities.NewManualClock(time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC))
+	original := newSnapshotLot(t, clock)
+	path := filepath.Join(t.TempDir(), "lot.json")
+	if err := original.SaveSnapshot(path); err != nil {
+		t.Fatal(err)
+	}
+	snapshot, err := LoadSnapshot(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	restored, err := RestoreParkingLotService(snapshot, WithClock(clock))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want, got := mustSnapshot(t, original), mustSnapshot(t, restored); !reflect.DeepEqual(want, got) {
+		t.Errorf("restored lot snapshots differently\n got: %+v\nwant: %+v", got, want)
+	}
+	if err := restored.verifySpots(snapshot); err != nil {
+		t.Error(err)
+	}
+
+	// Counters, including the state counts kept alongside the bitmaps
+	want, got := original.GetParkingLotStatus(), restored.GetParkingLotStatus()
+	want.EventSeq, got.EventSeq = 0, 0
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("restored status %+v, want %+v", got, want)
+	}
+	if !got.Floors[1].Closed || got.Floors[0].Spots[entities.CAR].OutOfService != 1 || got.Floors[0].Spots[entities.CAR].Reserved != 1 {
+		t.Errorf("restored floors %+v, want floor 2 closed and one car spot on floor 1 out of service and one reserved", got.Floors)
+	}
+
+	// Bitmaps: every search finds the same spots and every spot holds the same vehicle
+	for i, floor := range original.floors {
+		for spotType, spots := range floor.Spots {
+			other := restored.floors[i].Spots[spotType]
+			for length := 1; length <= 3; length++ {
+				wantID, wantErr := spots.FindVacantRun(length)
+				gotID, gotErr := other.FindVacantRun(length)
+				if gotID != wantID || (gotErr == nil) != (wantErr == nil) {
+					t.Errorf("floor %d %s: run of %d found at %d (%v), want %d (%v)", i+1, spotType, length, gotID, gotErr, wantID, wantErr)
+				}
+			}
+			for spotID := 1; spotID <= spots.GetTotalSpots(); spotID++ {
+				if plate(spots, spotID) != plate(other, spotID) {
+					t.Errorf("floor %d %s spot %d holds %q, want %q", i+1, spotType, spotID, plate(other, spotID), plate(spots, spotID))
+				}
+			}
+		}
+	}
+
+	// Active tickets are found by plate, exited ones are not
+	for _, numberPlate := range []string{"CAR-1", "TRUCK-1", "CAR-2"} {
+		wantTicket, err := original.GetActiveTicketByVehicle(numberPlate)
+		if err != nil {
+			t.Fatal(err)
+		}
+		gotTicket, err := restored.GetActiveTicketByVehicle(numberPlate)
+		if err != nil {
+			t.Errorf("%s: %v", numberPlate, err)
+			continue
+		}
+		if gotTicket.ID != wantTicket.ID || !reflect.DeepEqual(gotTicket.SpotIDs, wantTicket.SpotIDs) {
+			t.Errorf("%s has ticket %s in %v, want %s in %v", numberPlate, gotTicket.ID, gotTicket.SpotIDs, wantTicket.ID, wantTicket.SpotIDs)
+		}
+	}
+	if _, err := restored.GetActiveTicketByVehicle("EXITED-1"); !errors.Is(err, ErrVehicleNotParked) {
+		t.Errorf("looking up an exited vehicle: got %v, want ErrVehicleNotParked", err)
+	}
+
+	// Both lots carry on the same way
+	if _, err := restored.ConfirmArrival("BOOKED-1"); err != nil {
+		t.Errorf("arriving for the restored reservation: %v", err)
+	}
+	if _, err := restored.ParkVehicle(entities.NewCar("CAR-3")); !errors.Is(err, ErrParkingLotFull) {
+		t.Errorf("parking with the car spots taken, out of service or closed: got %v, want ErrParkingLotFull", err)
+	}
+}
+
+func TestRestoreRejectsInconsistentSnapshot(t *testing.T) {
+	clock := entities.NewManualClock(time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC))
+	snapshot := mustSnapshot(t, newSnapshotLot(t, clock))
+
+	// Without CAR-1's ticket its spot would be restored vacant, though the snapshot lists it occupied
+	tickets := snapshot.Tickets[:0]
+	for _, saved := range snapshot.Tickets {
+		if saved.NumberPlate != "CAR-1" {
+			tickets = append(tickets, saved)
+		}
+	}
+	snapshot.Tickets = tickets
+	snapshot.Payments = nil
+
+	if _, err := RestoreParkingLotService(snapshot, WithClock(clock)); !errors.Is(err, ErrInconsistentSnapshot) {
+		t.Errorf("got %v, want ErrInconsistentSnapshot", err)
+	}
+}
+
+// plate returns the number plate of the vehicle in a spot, or "" if it is empty
+func plate(spots entities.ParkingSpot, spotID int) string {
+	vehicle, err := spots.GetVehicle(spotID)
+	if err != nil || vehicle == nil {
+		return ""
+	}
+	return vehicle.GetNumberPlate()
+}
@@ -0,0 +1,309 @@
+package service
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"parkinglot/entities"
+)
+
+var ErrRepositoryClosed = errors.New("ticket repository is closed")
+
+// FileTicketRepository stores tickets durably in a local JSON-lines file
+// Every save appends the ticket's latest version and syncs it to disk. Only an index of where each
+// ticket's latest version lives, which plates are parked and when tickets entered is kept in
+// memory, so tickets are read back from the file when asked for
+// Superseded versions pile up in the file until Compact rewrites it
+type FileTicketRepository struct {
+	path    string
+	file    *os.File
+	size    int64                     // bytes of complete lines in the file
+	index   map[string]ticketLocation // ticketID -> latest version
+	active  map[string]string         // vehicle number plate -> active ticketID
+	byEntry []ticketEntry             // every ticket ordered by entry time
+	stale   int                       // superseded versions still in the file
+	mu      sync.RWMutex
+}
+
+// ticketLocation is where the latest version of a ticket sits in the file
+type ticketLocation struct {
+	offset int64
+	length int
+}
+
+// ticketEntry places a ticket in entry time order; a ticket's entry time never changes
+type ticketEntry struct {
+	id        string
+	entryTime time.Time
+}
+
+// OpenFileTicketRepository opens or creates a ticket file and indexes the tickets in it
+// A torn last line, left by a crash mid-save, is truncated
+func OpenFileTicketRepository(path string) (*FileTicketRepository, error) {
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	if err := syncDir(filepath.Dir(path)); err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	r := &FileTicketRepository{path: path, file: file}
+	if err := r.load(); err != nil {
+		file.Close()
+		return nil, fmt.Errorf("open ticket repository %s: %w", path, err)
+	}
+	return r, nil
+}
+
+// load indexes every complete line and truncates the file after the last one
+func (r *FileTicketRepository) load() error {
+	r.index = make(map[string]ticketLocation)
+	r.active = make(map[string]string)
+	r.byEntry = nil
+	r.stale = 0
+	r.size = 0
+
+	info, err := r.file.Stat()
+	if err != nil {
+		return err
+	}
+	if _, err := r.file.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+
+	reader := bufio.NewReader(r.file)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if err != nil {
+			break // End of file, or a last line that was never finished
+		}
+		var saved TicketSnapshot
+		if json.Unmarshal(line, &saved) != nil {
+			break
+		}
+		r.indexTicket(saved, r.size, len(line))
+		r.size += int64(len(line))
+	}
+	r.sortEntries()
+
+	if info.Size() > r.size {
+		if err := r.file.Truncate(r.size); err != nil {
+			return err
+		}
+		return r.file.Sync()
+	}
+	return nil
+}
+
+// indexTicket records the location of a ticket's latest version
+// New tickets are appended to byEntry, which the caller keeps sorted
+// Must be called with the lock held
+func (r *FileTicketRepository) indexTicket(saved TicketSnapshot, offset int64, length int) {
+	if _, exists := r.index[saved.ID]; exists {
+		r.stale++
+	} else {
+		r.byEntry = append(r.byEntry, ticketEntry{id: saved.ID, entryTime: saved.EntryTime})
+	}
+	r.index[saved.ID] = ticketLocation{offset: offset, length: length}
+
+	if saved.ExitTime.IsZero() && saved.VoidedAt.IsZero() {
+		r.active[saved.NumberPlate] = saved.ID
+	} else if r.active[saved.NumberPlate] == saved.ID {
+		delete(r.active, saved.NumberPlate)
+	}
+}
+
+// sortEntries orders byEntry by entry time
+// Must be called with the lock held
+func (r *FileTicketRepository) sortEntries() {
+	sort.Slice(r.byEntry, func(i, j int) bool {
+		return entryBefore(r.byEntry[i].entryTime, r.byEntry[i].id, r.byEntry[j].entryTime, r.byEntry[j].id)
+	})
+}
+
+func (r *FileTicketRepository) Save(ticket *entities.Ticket) error {
+	saved := ticketSnapshot(ticket)
+	line, err := json.Marshal(saved)
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.file == nil {
+		return ErrRepositoryClosed
+	}
+	if _, err := r.file.WriteAt(line, r.size); err != nil {
+		r.file.Truncate(r.size)
+		return err
+	}
+	if err := r.file.Sync(); err != nil {
+		r.file.Truncate(r.size)
+		return err
+	}
+
+	_, existed := r.index[ticket.ID]
+	r.indexTicket(saved, r.size, len(line))
+	r.size += int64(len(line))
+	if !existed {
+		// Tickets mostly arrive in entry order, so this is usually a no-op
+		i := len(r.byEntry) - 1
+		for i > 0 && entryBefore(r.byEntry[i].entryTime, r.byEntry[i].id, r.byEntry[i-1].entryTime, r.byEntry[i-1].id) {
+			r.byEntry[i], r.byEntry[i-1] = r.byEntry[i-1], r.byEntry[i]
+			i--
+		}
+	}
+	return nil
+}
+
+func (r *FileTicketRepository) Get(ticketID string) (*entities.Ticket, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if r.file == nil {
+		return nil, ErrRepositoryClosed
+	}
+	if _, exists := r.index[ticketID]; !exists {
+		return nil, ErrTicketNotFound
+	}
+	return r.read(ticketID)
+}
+
+func (r *FileTicketRepository) GetActiveByPlate(numberPlate string) (*entities.Ticket, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if r.file == nil {
+		return nil, ErrRepositoryClosed
+	}
+	ticketID, exists := r.active[numberPlate]
+	if !exists {
+		return nil, ErrVehicleNotParked
+	}
+	return r.read(ticketID)
+}
+
+func (r *FileTicketRepository) ListByTimeRange(from, to time.Time) ([]*entities.Ticket, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if r.file == nil {
+		return nil, ErrRepositoryClosed
+	}
+	start := sort.Search(len(r.byEntry), func(i int) bool {
+		return !r.byEntry[i].entryTime.Before(from)
+	})
+
+	var tickets []*entities.Ticket
+	for _, entry := range r.byEntry[start:] {
+		if !inTimeRange(entry.entryTime, from, to) {
+			break
+		}
+		ticket, err := r.read(entry.id)
+		if err != nil {
+			return nil, err
+		}
+		tickets = append(tickets, ticket)
+	}
+	return tickets, nil
+}
+
+func (r *FileTicketRepository) CountActive() int {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return len(r.active)
+}
+
+// read decodes the latest version of an indexed ticket from the file
+// Must be called with the lock held
+func (r *FileTicketRepository) read(ticketID string) (*entities.Ticket, error) {
+	line, err := r.readLine(ticketID)
+	if err != nil {
+		return nil, err
+	}
+
+	var saved TicketSnapshot
+	if err := json.Unmarshal(line, &saved); err != nil {
+		return nil, fmt.Errorf("decode ticket %s: %w", ticketID, err)
+	}
+	return saved.ticket()
+}
+
+// readLine reads the raw line holding the latest version of an indexed ticket
+// Must be called with the lock held
+func (r *FileTicketRepository) readLine(ticketID string) ([]byte, error) {
+	location := r.index[ticketID]
+	line := make([]byte, location.length)
+	if _, err := r.file.ReadAt(line, location.offset); err != nil {
+		return nil, fmt.Errorf("read ticket %s: %w", ticketID, err)
+	}
+	return line, nil
+}
+
+// Compact rewrites the file with only the latest version of each ticket
+// The new file replaces the old one atomically, so a crash mid-compaction loses nothing
+func (r *FileTicketRepository) Compact() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.file == nil {
+		return ErrRepositoryClosed
+	}
+
+	var data bytes.Buffer
+	for _, entry := range r.byEntry {
+		line, err := r.readLine(entry.id)
+		if err != nil {
+			return err
+		}
+		data.Write(line)
+	}
+	if err := writeFileAtomic(r.path, data.Bytes()); err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(r.path, os.O_RDWR, 0o644)
+	if err != nil {
+		return err
+	}
+	r.file.Close()
+	r.file = file
+	return r.load()
+}
+
+// Stale returns how many superseded ticket versions Compact would drop
+func (r *FileTicketRepository) Stale() int {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.stale
+}
+
+// Path returns the repository's file path
+func (r *FileTicketRepository) Path() string {
+	return r.path
+}
+
+func (r *FileTicketRepository) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.file == nil {
+		return nil
+	}
+	err := r.file.Close()
+	r.file = nil
+	return err
+}
@@ -0,0 +1,226 @@
+package service
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"parkinglot/entities"
+)
+
+var repositoryEpoch = time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+
+// openRepository opens a ticket file, closing it when the test ends
+func openRepository(t *testing.T, path string) *FileTicketRepository {
+	t.Helper()
+	repository, err := OpenFileTicketRepository(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { repository.Close() })
+	return repository
+}
+
+// repositoryTicket creates a car ticket that entered hours after the epoch
+func repositoryTicket(plate string, hours int) *entities.Ticket {
+	return entities.NewTicket(entities.NewCar(plate), 1, entities.CAR, []int{hours + 1}, 20, repositoryEpoch.Add(time.Duration(hours)*time.Hour))
+}
+
+// fillRepository saves an exited ticket, an active one saved twice and a second active one
+func fillRepository(t *testing.T, repository *FileTicketRepository) []*entities.Ticket {
+	t.Helper()
+	exited, paid, parked := repositoryTicket("CAR-1", 0), repositoryTicket("CAR-2", 1), repositoryTicket("CAR-3", 2)
+	for _, ticket := range []*entities.Ticket{exited, paid, parked} {
+		if err := repository.Save(ticket); err != nil {
+			t.Fatal(err)
+		}
+	}
+	exited.MarkExit(repositoryEpoch.Add(30 * time.Minute))
+	exited.Fee = 20
+	paid.PaidAmount, paid.PaidAt = 40, repositoryEpoch.Add(3*time.Hour)
+	for _, ticket := range []*entities.Ticket{exited, paid} {
+		if err := repository.Save(ticket); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return []*entities.Ticket{exited, paid, parked}
+}
+
+// checkRepository checks that a repository holds the latest versions of tickets
+func checkRepository(t *testing.T, repository *FileTicketRepository, tickets []*entities.Ticket) {
+	t.Helper()
+	for _, want := range tickets {
+		got, err := repository.Get(want.ID)
+		if err != nil {
+			t.Fatalf("Get(%s): %v", want.ID, err)
+		}
+		if !reflect.DeepEqual(ticketSnapshot(got), ticketSnapshot(want)) {
+			t.Errorf("Get(%s) = %+v, want %+v", want.ID, ticketSnapshot(got), ticketSnapshot(want))
+		}
+	}
+	if count := repository.CountActive(); count != 2 {
+		t.Errorf("%d active tickets, want 2", count)
+	}
+	if _, err := repository.GetActiveByPlate("CAR-1"); !errors.Is(err, ErrVehicleNotParked) {
+		t.Errorf("active ticket of an exited vehicle: got %v, want ErrVehicleNotParked", err)
+	}
+	if active, err := repository.GetActiveByPlate("CAR-2"); err != nil || active.PaidAmount != 40 {
+		t.Errorf("active ticket of CAR-2 is %+v, %v; want its latest version with 40 paid", active, err)
+	}
+}
+
+func TestFileTicketRepositoryReopens(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tickets.jsonl")
+	repository := openRepository(t, path)
+	tickets := fillRepository(t, repository)
+	repository.Close()
+	if _, err := repository.Get(tickets[0].ID); !errors.Is(err, ErrRepositoryClosed) {
+		t.Errorf("Get after Close: got %v, want ErrRepositoryClosed", err)
+	}
+
+	reopened := openRepository(t, path)
+	checkRepository(t, reopened, tickets)
+	if stale := reopened.Stale(); stale != 2 {
+		t.Errorf("%d stale versions after reopening, want 2", stale)
+	}
+}
+
+func TestFileTicketRepositoryTruncatesTornLine(t *testing.T) {
+	for name, tail := range map[string]string{
+		"unfinished line": `{"id":"20260302`,
+		"garbled line":    "{not json}\n",
+	} {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "tickets.jsonl")
+			repository := openRepository(t, path)
+			tickets := fillRepository(t, repository)
+			repository.Close()
+
+			intact, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(path, append(intact, tail...), 0o644); err != nil {
+				t.Fatal(err)
+			}
+
+			reopened := openRepository(t, path)
+			checkRepository(t, reopened, tickets)
+			if data, _ := os.ReadFile(path); len(data) != len(intact) {
+				t.Errorf("file is %d bytes after reopening, want the %d intact ones", len(data), len(intact))
+			}
+
+			// New saves follow straight on from the last intact line
+			next := repositoryTicket("CAR-4", 3)
+			if err := reopened.Save(next); err != nil {
+				t.Fatal(err)
+			}
+			reopened.Close()
+			if _, err := openRepository(t, path).Get(next.ID); err != nil {
+				t.Errorf("ticket saved after the truncation: %v", err)
+			}
+		})
+	}
+}
+
+func TestFileTicketRepositoryCompact(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tickets.jsonl")
+	repository := openRepository(t, path)
+	tickets := fillRepository(t, repository)
+	before, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := repository.Compact(); err != nil {
+		t.Fatal(err)
+	}
+	if stale := repository.Stale(); stale != 0 {
+		t.Errorf("%d stale versions after compacting, want 0", stale)
+	}
+	after, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if after.Size() >= before.Size() {
+		t.Errorf("file is %d bytes after compacting, was %d", after.Size(), before.Size())
+	}
+	checkRepository(t, repository, tickets)
+
+	// The compacted file keeps taking saves and reads back the same after reopening
+	tickets[2].PaidAmount = 60
+	if err := repository.Save(tickets[2]); err != nil {
+		t.Fatal(err)
+	}
+	repository.Close()
+	checkRepository(t, openRepository(t, path), tickets)
+}
+
+func TestFileTicketRepositoryListByTimeRange(t *testing.T) {
+	repository := openRepository(t, filepath.Join(t.TempDir(), "tickets.jsonl"))
+	// Saved out of entry order, to check they are listed oldest first regardless
+	for _, hours := range []int{2, 0, 1} {
+		if err := repository.Save(repositoryTicket("CAR", hours)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	at := func(hours int) time.Time { return repositoryEpoch.Add(time.Duration(hours) * time.Hour) }
+	tests := []struct {
+		name      string
+		from, to  time.Time
+		wantHours []int
+	}{
+		{"everything", time.Time{}, time.Time{}, []int{0, 1, 2}},
+		{"from is inclusive", at(1), time.Time{}, []int{1, 2}},
+		{"to is exclusive", time.Time{}, at(1), []int{0}},
+		{"single hour", at(1), at(2), []int{1}},
+		{"empty range", at(1), at(1), nil},
+		{"after every ticket", at(3), time.Time{}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tickets, err := repository.ListByTimeRange(tt.from, tt.to)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var gotHours []int
+			for _, ticket := range tickets {
+				gotHours = append(gotHours, int(ticket.EntryTime.Sub(repositoryEpoch)/time.Hour))
+			}
+			if !reflect.DeepEqual(gotHours, tt.wantHours) {
+				t.Errorf("listed tickets entering at hours %v, want %v", gotHours, tt.wantHours)
+			}
+		})
+	}
+}
+
+func TestFileTicketRepositoryGetReturnsCopy(t *testing.T) {
+	repository := openRepository(t, filepath.Join(t.TempDir(), "tickets.jsonl"))
+	ticket := repositoryTicket("CAR-1", 0)
+	if err := repository.Save(ticket); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := repository.Get(ticket.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got.PaidAmount = 999
+	got.SpotIDs[0] = 99
+	ticket.Fee = 999 // Nor does the saved ticket share anything with what is stored
+
+	again, err := repository.Get(ticket.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again == got || again.PaidAmount != 0 || again.SpotIDs[0] != 1 || again.Fee != 0 {
+		t.Errorf("Get returned %+v after changing earlier copies, want the ticket as saved", again)
+	}
+	if active, _ := repository.GetActiveByPlate("CAR-1"); active == again {
+		t.Error("GetActiveByPlate and Get returned the same ticket value")
+	}
+}
@@ -0,0 +1,125 @@
+package service
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"parkinglot/entities"
+)
+
+// TicketRepository stores the lot's tickets
+// The service saves a ticket every time it changes, so an implementation only has to keep the
+// latest version of each; Get and GetActiveByPlate return ErrTicketNotFound and ErrVehicleNotParked
+// when there is no match
+type TicketRepository interface {
+	Save(ticket *entities.Ticket) error
+	Get(ticketID string) (*entities.Ticket, error)
+	GetActiveByPlate(numberPlate string) (*entities.Ticket, error)
+	// ListByTimeRange returns tickets with an entry time in [from, to), oldest first
+	// A zero to means no upper bound
+	ListByTimeRange(from, to time.Time) ([]*entities.Ticket, error)
+	CountActive() int
+	Close() error
+}
+
+// WithTicketRepository sets where tickets are stored
+// Defaults to a MemoryTicketRepository
+func WithTicketRepository(repository TicketRepository) Option {
+	return func(pls *ParkingLotService) {
+		if repository != nil {
+			pls.tickets = repository
+		}
+	}
+}
+
+// MemoryTicketRepository keeps tickets in maps
+// Saved tickets are kept by pointer, so every holder of a ticket sees its latest state
+type MemoryTicketRepository struct {
+	tickets map[string]*entities.Ticket // ticketID -> ticket
+	active  map[string]*entities.Ticket // vehicle number plate -> active ticket
+	mu      sync.RWMutex
+}
+
+// NewMemoryTicketRepository creates an empty MemoryTicketRepository
+func NewMemoryTicketRepository() *MemoryTicketRepository {
+	return &MemoryTicketRepository{
+		tickets: make(map[string]*entities.Ticket),
+		active:  make(map[string]*entities.Ticket),
+	}
+}
+
+func (r *MemoryTicketRepository) Save(ticket *entities.Ticket) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.tickets[ticket.ID] = ticket
+	plate := ticket.Vehicle.GetNumberPlate()
+	if ticket.IsActive() {
+		r.active[plate] = ticket
+	} else if current, ok := r.active[plate]; ok && current.ID == ticket.ID {
+		delete(r.active, plate)
+	}
+	return nil
+}
+
+func (r *MemoryTicketRepository) Get(ticketID string) (*entities.Ticket, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	ticket, exists := r.tickets[ticketID]
+	if !exists {
+		return nil, ErrTicketNotFound
+	}
+	return ticket, nil
+}
+
+func (r *MemoryTicketRepository) GetActiveByPlate(numberPlate string) (*entities.Ticket, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	ticket, exists := r.active[numberPlate]
+	if !exists {
+		return nil, ErrVehicleNotParked
+	}
+	return ticket, nil
+}
+
+func (r *MemoryTicketRepository) ListByTimeRange(from, to time.Time) ([]*entities.Ticket, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var tickets []*entities.Ticket
+	for _, ticket := range r.tickets {
+		if inTimeRange(ticket.EntryTime, from, to) {
+			tickets = append(tickets, ticket)
+		}
+	}
+	sort.Slice(tickets, func(i, j int) bool {
+		return entryBefore(tickets[i].EntryTime, tickets[i].ID, tickets[j].EntryTime, tickets[j].ID)
+	})
+	return tickets, nil
+}
+
+func (r *MemoryTicketRepository) CountActive() int {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return len(r.active)
+}
+
+func (r *MemoryTicketRepository) Close() error {
+	return nil
+}
+
+// inTimeRange reports whether t falls in [from, to), where a zero to means no upper bound
+func inTimeRange(t, from, to time.Time) bool {
+	return !t.Before(from) && (to.IsZero() || t.Before(to))
+}
+
+// entryBefore orders tickets by entry time, then by ID for tickets issued at the same instant
+func entryBefore(aTime time.Time, aID string, bTime time.Time, bID string) bool {
+	if aTime.Equal(bTime) {
+		return aID < bID
+	}
+	return aTime.Before(bTime)
+}